@@ -0,0 +1,60 @@
+package rope
+
+import "sync"
+
+const DefaultChunkSize = 256
+
+// Allocates nodes in chunks instead of one by one, reducing GC pressure
+// during heavy editing. It can be shared by many ropes, but only ropes of
+// a single element type benefit at a time.
+type Arena struct {
+	ChunkSize int // Nodes allocated at once, DefaultChunkSize if 0
+	mutex     sync.Mutex
+	chunk     any // []Rope[T] with the nodes not yet handed out
+}
+
+func NewArena(chunkSize int) *Arena {
+	return &Arena{ChunkSize: chunkSize}
+}
+
+// Drops the nodes not yet handed out, to be used when a document is closed.
+// Existing ropes stay valid, and each chunk is collected once none of its
+// nodes are reachable.
+func (a *Arena) Release() {
+	a.mutex.Lock()
+	a.chunk = nil
+	a.mutex.Unlock()
+}
+
+func newNode[T any](settings *Settings) *Rope[T] {
+	if settings.Metrics != nil {
+		settings.Metrics.nodesAllocated.Add(1)
+	}
+	if settings.Pool != nil {
+		if node := takeNode[T](settings.Pool); node != nil {
+			node.settings = settings
+			return node
+		}
+	}
+	if settings.Arena == nil {
+		return &Rope[T]{settings: settings}
+	}
+	node := allocate[T](settings.Arena)
+	node.settings = settings
+	return node
+}
+
+func allocate[T any](a *Arena) *Rope[T] {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	chunk, _ := a.chunk.([]Rope[T])
+	if len(chunk) == 0 {
+		chunkSize := a.ChunkSize
+		if chunkSize <= 0 {
+			chunkSize = DefaultChunkSize
+		}
+		chunk = make([]Rope[T], chunkSize)
+	}
+	a.chunk = chunk[1:]
+	return &chunk[0]
+}
@@ -0,0 +1,22 @@
+package rope
+
+import "testing"
+
+func TestArena(t *testing.T) {
+	settings := *testSettings
+	settings.Arena = NewArena(16)
+
+	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	rope := NewRope(originalValue, &settings)
+	newRope := rope.Insert(2, []int{-1, -2, -3}).Remove(0, 1)
+	settings.Arena.Release()
+	newestRope := newRope.Insert(0, []int{0})
+
+	assertValue(t, rope, originalValue)
+	assertValue(t, newRope, []int {
+		1, -1, -2, -3, 2, 3, 4, 5, 6, 7,
+	})
+	assertValue(t, newestRope, []int {
+		0, 1, -1, -2, -3, 2, 3, 4, 5, 6, 7,
+	})
+}
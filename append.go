@@ -0,0 +1,92 @@
+package rope
+
+import "sync/atomic"
+
+// Shared by the leaves viewing the same backing array, recording how much
+// of it is in use. A leaf may only extend into the spare capacity if it
+// ends where the claimed part does, as otherwise another version
+// has already written there.
+type claim struct {
+	length atomic.Int64
+}
+
+// Inserts the values at the end. If nothing else has appended to the
+// same version, the rightmost leaf is extended in place, making
+// consecutive appends amortized O(1) instead of copying the leaf each time.
+func (r *Rope[T]) Append(values []T) *Rope[T] {
+	must(r.checkGrowth(len(values)))
+	length := r.length
+	changed := r.appendValues(values).limitDepth(0)
+	changed = changed.autoRebalance()
+	changed.logOperation("Append", length, changed.length)
+	return changed
+}
+
+func (r *Rope[T]) appendValues(values []T) *Rope[T] {
+	if len(values) == 0 {
+		return r
+	}
+	if r.piece {
+		return r.expand().appendValues(values)
+	}
+	if r.value != nil { // Isn't split
+		if r.length + len(values) > r.settings.SplitLength { // No room to extend it in place
+			return r.insertBeside(r.length, values)
+		}
+		return r.appendLeaf(values)
+	}
+	// Is split
+	changed := newNode[T](r.settings)
+	changed.length = r.length + len(values)
+	changed.left = r.left
+	changed.right = r.right.appendValues(values)
+	changed.updateHeight()
+	return changed
+}
+
+func (r *Rope[T]) appendLeaf(values []T) *Rope[T] {
+	length := r.length + len(values)
+	changed := newNode[T](r.settings)
+	changed.length = length
+
+	if r.claim != nil && length <= cap(r.value) &&
+	   r.claim.length.CompareAndSwap(int64(r.length), int64(length)) {
+		// The spare capacity is ours, other versions only see up to r.length
+		changed.value = r.value[:length]
+		changed.claim = r.claim
+		copy(changed.value[r.length:], values)
+		r.countCopied(len(values))
+		changed.adjust()
+		return changed
+	}
+
+	capacity := 2 * length // Leaves up to SplitLength don't need to grow again
+	if capacity > r.settings.SplitLength {
+		capacity = r.settings.SplitLength
+	}
+	if capacity < length {
+		capacity = length
+	}
+	changed.value = make([]T, length, capacity)
+	copy(changed.value, r.value)
+	copy(changed.value[r.length:], values)
+	r.countCopied(length)
+	changed.claim = &claim{}
+	changed.claim.length.Store(int64(length))
+	changed.adjust()
+	return changed
+}
+
+// Splits the leaf at index, leaving the insertion at the end of the left
+// side with spare capacity after it, which works as the gap of a gap buffer:
+// further insertions there are appends to the left leaf.
+// The right side keeps referencing the original backing array.
+func (r *Rope[T]) openGap(index int, insertion []T) *Rope[T] {
+	r.disown()
+	changed := newNode[T](r.settings)
+	changed.length = r.length + len(insertion)
+	changed.left = NewRope(r.value[:index:index], r.settings).appendLeaf(insertion)
+	changed.right = NewRope(r.value[index:r.length:r.length], r.settings)
+	changed.updateHeight()
+	return changed
+}
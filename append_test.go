@@ -0,0 +1,48 @@
+package rope
+
+import "testing"
+
+func TestAppend(t *testing.T) {
+	expected := []int{}
+	rope := NewRope([]int{}, DefaultSettings)
+	for i := 0; i < 1000; i++ {
+		rope = rope.Append([]int{i, -i})
+		expected = append(expected, i, -i)
+	}
+	assertValue(t, rope, expected)
+}
+
+func TestAppendSharing(t *testing.T) {
+	base := NewRope([]int{}, DefaultSettings).Append([]int{0, 1}).Append([]int{2})
+	first := base.Append([]int{3, 4})
+	second := base.Append([]int{-3})
+	third := first.Append([]int{5})
+
+	assertValue(t, base, []int{0, 1, 2})
+	assertValue(t, first, []int{0, 1, 2, 3, 4})
+	assertValue(t, second, []int{0, 1, 2, -3})
+	assertValue(t, third, []int{0, 1, 2, 3, 4, 5})
+}
+
+func TestGapBuffer(t *testing.T) {
+	settings := *DefaultSettings
+	settings.GapBuffer = true
+
+	rope := NewRope(make([]int, 1000), &settings)
+	expected := make([]int, 1000)
+	var middle *Rope[int]
+	var middleValue []int
+	for i := 1; i <= 500; i++ {
+		rope = rope.Insert(300 + i - 1, []int{i})
+		expected = append(expected[:300 + i - 1], append([]int{i}, expected[300 + i - 1:]...)...)
+		if i == 250 {
+			middle = rope
+			middleValue = append([]int{}, expected...)
+		}
+	}
+	branch := middle.Insert(300 + 250, []int{-1})
+
+	assertValue(t, rope, expected)
+	assertValue(t, middle, middleValue)
+	assertValue(t, branch, append(append(append([]int{}, middleValue[:550]...), -1), middleValue[550:]...))
+}
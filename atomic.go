@@ -0,0 +1,37 @@
+package rope
+
+import "sync/atomic"
+
+// Holds a rope which can be read and updated from many goroutines.
+// As ropes are persistent, readers never block, and writers
+// retry their update if another one got there first.
+type AtomicRope[T any] struct {
+	pointer atomic.Pointer[Rope[T]]
+}
+
+func NewAtomicRope[T any](rope *Rope[T]) *AtomicRope[T] {
+	atomicRope := &AtomicRope[T]{}
+	atomicRope.pointer.Store(rope)
+	return atomicRope
+}
+
+func (a *AtomicRope[T]) Load() *Rope[T] {
+	return a.pointer.Load()
+}
+
+func (a *AtomicRope[T]) Store(rope *Rope[T]) {
+	a.pointer.Store(rope)
+}
+
+// Applies the update to the current rope, retrying on conflict,
+// so update may be called more than once and must not have side effects.
+// Returns the rope that was stored.
+func (a *AtomicRope[T]) Update(update func(*Rope[T]) *Rope[T]) *Rope[T] {
+	for {
+		old := a.pointer.Load()
+		changed := update(old)
+		if a.pointer.CompareAndSwap(old, changed) {
+			return changed
+		}
+	}
+}
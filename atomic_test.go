@@ -0,0 +1,28 @@
+package rope
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicUpdate(t *testing.T) {
+	const goroutines = 8
+	const n = 100
+	atomicRope := NewAtomicRope(NewRope([]int{}, testSettings))
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				atomicRope.Update(func(r *Rope[int]) *Rope[int] {
+					return r.Insert(r.Length(), []int{j})
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert(t, atomicRope.Load().Length() == goroutines * n, "Lost updates:", atomicRope.Load().Length())
+}
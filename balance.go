@@ -0,0 +1,82 @@
+package rope
+
+// Returns the worst ratio between the lengths of the sides of a node,
+// which Rebalance compares against Settings.Rebalance.
+func (r *Rope[T]) BalanceFactor() float32 {
+	if r.value != nil {
+		return 1
+	}
+	factor := float32(r.left.length) / float32(r.right.length)
+	if factor < 1 {
+		factor = 1 / factor
+	}
+	if left := r.left.BalanceFactor(); left > factor {
+		factor = left
+	}
+	if right := r.right.BalanceFactor(); right > factor {
+		factor = right
+	}
+	return factor
+}
+
+// Whether Rebalance would leave the rope as it is
+func (r *Rope[T]) IsBalanced() bool {
+	if r.value != nil {
+		return true
+	}
+	if r.balancer().ShouldRebalance(r.info()) {
+		return false
+	}
+	return r.left.IsBalanced() && r.right.IsBalanced()
+}
+
+// A single leaf has a depth of 1
+func (r *Rope[T]) Depth() int {
+	return r.height + 1
+}
+
+// Returns the depth of a rope of the same length built from scratch,
+// the best Rebalance can do.
+func (r *Rope[T]) IdealDepth() int {
+	depth := 1
+	for length := r.length; length > r.settings.SplitLength; length -= length / 2 {
+		depth++
+	}
+	return depth
+}
+
+// Returns the result of an edit rebalanced with AutoRebalance. This walks
+// the whole tree, so it is better suited to ropes edited in bursts. As
+// edits may return nodes of the version they were applied to, or r
+// itself, nothing is modified in place.
+func (r *Rope[T]) autoRebalance() *Rope[T] {
+	if !r.settings.AutoRebalance {
+		return r
+	}
+	return r.rebalanced(0, r.length)
+}
+
+// Rebuilds the subtrees making the rope deeper than MaxDepth, choosing
+// the smallest ones that can be rebuilt within it. As edits only make
+// the edited paths deeper, this is usually a small part of the rope.
+func (r *Rope[T]) limitDepth(level int) *Rope[T] {
+	maxDepth := r.settings.MaxDepth
+	if maxDepth == 0 || level + r.Depth() <= maxDepth {
+		return r
+	}
+	if r.value == nil && level + 1 + r.left.IdealDepth() <= maxDepth &&
+	   level + 1 + r.right.IdealDepth() <= maxDepth {
+		changed := link(r.left.limitDepth(level + 1), r.right.limitDepth(level + 1))
+		if level + changed.Depth() <= maxDepth {
+			return changed
+		}
+	}
+	rebuilt := buildRope(r.Value(), r.settings)
+	if r.settings.Metrics != nil {
+		r.settings.Metrics.rebalances.Add(1)
+	}
+	if r.settings.OnRebalance != nil {
+		r.settings.OnRebalance(r.length)
+	}
+	return rebuilt
+}
@@ -0,0 +1,58 @@
+package rope
+
+import "testing"
+
+func TestBalance(t *testing.T) {
+	settings := *testSettings
+	settings.Rebalance = 1.5
+	rope := NewRope([]int{}, &settings)
+	for i := 0; i < 100; i++ {
+		rope = rope.Insert(0, []int{0, 1, 2, 3})
+	}
+	assert(t, !rope.IsBalanced(), "Rope should be unbalanced")
+	assert(t, rope.BalanceFactor() > 10, "Balance factor too low:", rope.BalanceFactor())
+	assert(t, rope.Depth() == maxDepth(rope), "Wrong depth:", rope.Depth())
+	assert(t, rope.Depth() > rope.IdealDepth(), "Depth should be worse than ideal")
+
+	rope.Rebalance()
+	assert(t, rope.IsBalanced(), "Rope should be balanced, factor:", rope.BalanceFactor())
+	assert(t, rope.Depth() == rope.IdealDepth(), "Wrong ideal depth:", rope.IdealDepth(), rope.Depth())
+}
+
+func TestRebalanced(t *testing.T) {
+	settings := *testSettings
+	settings.Rebalance = 1.5
+	rope := NewRope([]int{}, &settings)
+	for i := 0; i < 100; i++ {
+		rope = rope.Insert(0, []int{0, 1, 2, 3})
+	}
+	concatenated := rope.Concat(NewRope([]int{4, 5, 6}, &settings))
+	value := rope.Value()
+	rebalanced := rope.Freeze().Rebalanced()
+
+	assert(t, rebalanced.IsBalanced() && rebalanced.Depth() == rebalanced.IdealDepth(), "Not rebalanced:", rebalanced.Depth())
+	assertValue(t, rebalanced, value)
+	assert(t, !rope.IsBalanced(), "Original rebalanced in place")
+	assertValue(t, rope, value)
+	assert(t, concatenated.Validate() == nil, "Version sharing the root modified:", concatenated.Validate())
+}
+
+func TestMaxDepth(t *testing.T) {
+	settings := *testSettings
+	settings.MaxDepth = 10
+	rebuilt := 0
+	settings.OnRebalance = func(length int) {
+		rebuilt += length
+	}
+	rope := Empty[int](&settings)
+	expected := []int{}
+	for i := 0; i < 500; i++ {
+		rope = rope.Insert(0, []int{i, i})
+		expected = append([]int{i, i}, expected...)
+		assert(t, rope.Depth() <= 10, "Too deep:", rope.Depth())
+	}
+	assertValue(t, rope, expected)
+	assert(t, rope.Depth() == maxDepth(rope), "Wrong depth:", rope.Depth(), maxDepth(rope))
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+	assert(t, rebuilt < 500 * 1000 / 4, "Rebuilt too much:", rebuilt)
+}
@@ -0,0 +1,183 @@
+package rope
+
+// The lengths of a split node and its sides, for deciding whether to
+// rebalance it
+type Info struct {
+	Length int
+	Left   int
+	Right  int
+}
+
+// Whether either side is longer than the other by more than the ratio
+func (i Info) Exceeds(ratio float32) bool {
+	return float32(i.Left) / float32(i.Right) > ratio || float32(i.Right) / float32(i.Left) > ratio
+}
+
+// How Rebalance fixes the split nodes, set in Settings.Balancer.
+// Nodes are checked from the root down, and the ones that should be
+// rebalanced are replaced by the result of Rebalance, which must have
+// the same content and must not modify them, as they may be shared.
+type Balancer[T any] interface {
+	ShouldRebalance(node Info) bool
+	Rebalance(r *Rope[T]) *Rope[T]
+}
+
+// Rebuilds unbalanced nodes from their values, which is the default,
+// with Settings.Rebalance as the ratio. It gives the best trees,
+// but copies everything below the nodes.
+type FlattenBalancer[T any] struct {
+	Ratio float32
+}
+
+func (b FlattenBalancer[T]) ShouldRebalance(node Info) bool {
+	return node.Exceeds(b.Ratio)
+}
+
+func (b FlattenBalancer[T]) Rebalance(r *Rope[T]) *Rope[T] {
+	return NewRope(r.Value(), r.settings)
+}
+
+// Relinks the leaves of unbalanced nodes with the algorithm of Boehm et
+// al., keeping subtrees in slots by Fibonacci length ranges. No leaves
+// are copied, except short ones being joined, though the trees are less
+// balanced than with FlattenBalancer.
+type FibonacciBalancer[T any] struct {
+	Ratio float32
+}
+
+func (b FibonacciBalancer[T]) ShouldRebalance(node Info) bool {
+	return node.Exceeds(b.Ratio)
+}
+
+func (b FibonacciBalancer[T]) Rebalance(r *Rope[T]) *Rope[T] {
+	fibonacci := []int{1, 2}
+	slots := []*Rope[T]{} // Slot i is nil or between fibonacci[i] and fibonacci[i + 1]
+	slotFor := func(length int) int {
+		i := 0
+		for ; length >= fibonacci[i + 1]; i++ {
+			if i + 2 == len(fibonacci) {
+				fibonacci = append(fibonacci, fibonacci[i] + fibonacci[i + 1])
+			}
+		}
+		for len(slots) <= i {
+			slots = append(slots, nil)
+		}
+		return i
+	}
+	var add func(node *Rope[T])
+	add = func(node *Rope[T]) {
+		if node.value == nil {
+			add(node.left)
+			add(node.right)
+			return
+		}
+		for {
+			i := slotFor(node.length)
+			var prefix *Rope[T] // Higher slots have the earlier content
+			for j := len(slots) - 1; j >= 0; j-- {
+				if j <= i && slots[j] != nil {
+					prefix = link(prefix, slots[j])
+					slots[j] = nil
+				}
+			}
+			if prefix == nil {
+				slots[i] = node
+				return
+			}
+			node = link(prefix, node)
+		}
+	}
+	add(r)
+	var linked *Rope[T]
+	for j := len(slots) - 1; j >= 0; j-- {
+		if slots[j] != nil {
+			linked = link(linked, slots[j])
+		}
+	}
+	return linked
+}
+
+// Moves subtrees from the longer side of unbalanced nodes to the
+// shorter one with tree rotations, then fixes the sides the same way.
+// Nothing is copied, except short leaves being joined, but every node
+// below an unbalanced one is visited.
+type RotationBalancer[T any] struct {
+	Ratio float32
+}
+
+func (b RotationBalancer[T]) ShouldRebalance(node Info) bool {
+	return node.Exceeds(b.Ratio)
+}
+
+func (b RotationBalancer[T]) Rebalance(r *Rope[T]) *Rope[T] {
+	if r.value != nil {
+		return r
+	}
+	for b.ShouldRebalance(r.info()) {
+		rotated := r.rotate()
+		if rotated == r || rotated.value != nil || rotated.balanceRatio() >= r.balanceRatio() {
+			break
+		}
+		r = rotated
+	}
+	if r.value != nil {
+		return r
+	}
+	left, right := b.Rebalance(r.left), b.Rebalance(r.right)
+	if left == r.left && right == r.right {
+		return r
+	}
+	return link(left, right)
+}
+
+// Rotates a subtree from the longer side to the shorter one,
+// returning r if the longer side is a leaf
+func (r *Rope[T]) rotate() *Rope[T] {
+	if r.left.length > r.right.length && r.left.value == nil {
+		return link(r.left.left, link(r.left.right, r.right))
+	}
+	if r.right.length > r.left.length && r.right.value == nil {
+		return link(link(r.left, r.right.left), r.right.right)
+	}
+	return r
+}
+
+func (r *Rope[T]) balanceRatio() float32 {
+	if r.value != nil {
+		return 1
+	}
+	ratio := float32(r.left.length) / float32(r.right.length)
+	if ratio < 1 {
+		return 1 / ratio
+	}
+	return ratio
+}
+
+// Returns a node with the sides, joined if it is short,
+// or the other side if one is nil
+func link[T any](left, right *Rope[T]) *Rope[T] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	node := newNode[T](left.settings)
+	node.left = left
+	node.right = right
+	node.length = left.length + right.length
+	node.adjust()
+	return node
+}
+
+func (r *Rope[T]) info() Info {
+	return Info{r.length, r.left.length, r.right.length}
+}
+
+// Returns the Balancer in the settings, or a FlattenBalancer
+func (r *Rope[T]) balancer() Balancer[T] {
+	if r.settings.Balancer != nil {
+		return r.settings.Balancer.(Balancer[T])
+	}
+	return FlattenBalancer[T]{r.settings.Rebalance}
+}
@@ -0,0 +1,38 @@
+package rope
+
+import "testing"
+
+func TestBalancers(t *testing.T) {
+	balancers := []Balancer[int]{
+		FlattenBalancer[int]{1.5},
+		FibonacciBalancer[int]{1.5},
+		RotationBalancer[int]{1.5},
+	}
+	for _, balancer := range balancers {
+		settings := *testSettings
+		settings.Balancer = balancer
+		rope := Empty[int](&settings)
+		expected := []int{}
+		for i := 0; i < 100; i++ {
+			rope = rope.Insert(0, []int{i, i, i})
+			expected = append([]int{i, i, i}, expected...)
+		}
+		original := rope.Value()
+		depth := rope.Depth()
+		unbalanced := *rope
+
+		rope.Rebalance()
+		assertValue(t, rope, expected)
+		assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+		assert(t, rope.Depth() < depth / 2, "Not rebalanced:", rope.Depth(), depth)
+		assertValue(t, &unbalanced, original)
+		assert(t, unbalanced.Depth() == depth, "Shared nodes modified")
+	}
+}
+
+func TestDefaultBalancer(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	_, ok := rope.balancer().(FlattenBalancer[int])
+	assert(t, ok, "Wrong default:", rope.balancer())
+	assert(t, Info{10, 6, 4}.Exceeds(1.4) && !Info{10, 6, 4}.Exceeds(1.5), "Wrong ratio")
+}
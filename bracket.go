@@ -0,0 +1,128 @@
+package rope
+
+import "strings"
+
+// The brackets most languages use, for MatchingBracket
+const DefaultBrackets = "()[]{}"
+
+// Summaries are kept for each pair of brackets asked about
+type bracketKey struct {
+	open, close byte
+}
+
+// The brackets of a pair left unmatched in a node, which are all the
+// closing ones before the opening ones
+type bracketCounts struct {
+	closes, opens int
+}
+
+func bracketSummary(r *Rope[byte], key bracketKey) bracketCounts {
+	return cachedSummary(r, key, func() bracketCounts {
+		if r.value == nil {
+			left, right := bracketSummary(r.left, key), bracketSummary(r.right, key)
+			matched := min(left.opens, right.closes)
+			return bracketCounts{left.closes + right.closes - matched, left.opens + right.opens - matched}
+		}
+		counts := bracketCounts{}
+		for _, b := range r.value {
+			switch {
+			case b == key.open:
+				counts.opens++
+			case b != key.close:
+			case counts.opens > 0:
+				counts.opens--
+			default:
+				counts.closes++
+			}
+		}
+		return counts
+	})
+}
+
+// Returns the offset of the bracket matching the one at offset, and
+// whether there is one. pairs has the opening and closing bytes of each
+// pair one after the other, as in DefaultBrackets, and brackets of other
+// pairs are ignored, so in "(]" the parenthesis is unmatched. The count
+// of unmatched brackets of each pair is cached on the nodes the first
+// time it is asked for, after which it takes O(log n).
+func MatchingBracket(r *Rope[byte], offset int, pairs string) (int, bool) {
+	offset = r.mustIndex(offset, r.length - 1)
+	b := r.At(offset)
+	i := strings.IndexByte(pairs, b)
+	if i < 0 || len(pairs) % 2 != 0 {
+		return 0, false
+	}
+	key := bracketKey{pairs[i &^ 1], pairs[i | 1]}
+	if key.open == key.close {
+		return 0, false
+	}
+	depth := 0
+	if b == key.open {
+		return matchForward(r, offset + 1, key, &depth)
+	}
+	return matchBackward(r, offset, key, &depth)
+}
+
+// Returns the first closing bracket from start without an opening one
+// after depth ones, keeping in depth the ones opened before it
+func matchForward(r *Rope[byte], start int, key bracketKey, depth *int) (int, bool) {
+	if start == 0 {
+		if counts := bracketSummary(r, key); counts.closes <= *depth {
+			*depth += counts.opens - counts.closes
+			return 0, false
+		}
+	}
+	if r.value != nil {
+		for i := start; i < r.length; i++ {
+			switch {
+			case r.value[i] == key.open:
+				*depth++
+			case r.value[i] != key.close:
+			case *depth == 0:
+				return i, true
+			default:
+				*depth--
+			}
+		}
+		return 0, false
+	}
+	if start < r.left.length {
+		if found, ok := matchForward(r.left, start, key, depth); ok {
+			return found, true
+		}
+	}
+	if found, ok := matchForward(r.right, max(0, start - r.left.length), key, depth); ok {
+		return r.left.length + found, true
+	}
+	return 0, false
+}
+
+// Like matchForward, from before end to the start
+func matchBackward(r *Rope[byte], end int, key bracketKey, depth *int) (int, bool) {
+	if end == r.length {
+		if counts := bracketSummary(r, key); counts.opens <= *depth {
+			*depth += counts.closes - counts.opens
+			return 0, false
+		}
+	}
+	if r.value != nil {
+		for i := end - 1; i >= 0; i-- {
+			switch {
+			case r.value[i] == key.close:
+				*depth++
+			case r.value[i] != key.open:
+			case *depth == 0:
+				return i, true
+			default:
+				*depth--
+			}
+		}
+		return 0, false
+	}
+	if end > r.left.length {
+		if found, ok := matchBackward(r.right, end - r.left.length, key, depth); ok {
+			return r.left.length + found, true
+		}
+	}
+	return matchBackward(r.left, min(end, r.left.length), key, depth)
+}
@@ -0,0 +1,60 @@
+package rope
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Finds the matching bracket scanning the bytes
+func naiveMatchingBracket(text []byte, offset int, pairs string) (int, bool) {
+	i := strings.IndexByte(pairs, text[offset])
+	if i < 0 {
+		return 0, false
+	}
+	open, close, step := pairs[i &^ 1], pairs[i | 1], 1
+	if text[offset] == close {
+		open, close, step = close, open, -1
+	}
+	depth := 0
+	for j := offset + step; j >= 0 && j < len(text); j += step {
+		switch text[j] {
+		case open:
+			depth++
+		case close:
+			if depth == 0 {
+				return j, true
+			}
+			depth--
+		}
+	}
+	return 0, false
+}
+
+func TestMatchingBracket(t *testing.T) {
+	text := []byte("f(a[0], {b: (c)}) ]")
+	rope := NewRope(text, testSettings)
+	found, ok := MatchingBracket(rope, 1, DefaultBrackets)
+	assert(t, ok && found == 16, "Wrong match of an opening bracket", found)
+	found, ok = MatchingBracket(rope, 15, DefaultBrackets)
+	assert(t, ok && found == 8, "Wrong match of a closing bracket", found)
+	_, ok = MatchingBracket(rope, 18, DefaultBrackets)
+	assert(t, !ok, "Unmatched bracket matched")
+	_, ok = MatchingBracket(rope, 0, DefaultBrackets)
+	assert(t, !ok, "Other byte matched")
+	found, ok = MatchingBracket(rope, 1, "<>()")
+	assert(t, ok && found == 16, "Wrong match with other pairs", found)
+	assertPanics(t, ErrIndexOutOfRange, func() { MatchingBracket(rope, len(text), DefaultBrackets) })
+
+	random := rand.New(rand.NewSource(1))
+	text = make([]byte, 2000)
+	for i := range text {
+		text[i] = "()[]ab"[random.Intn(6)]
+	}
+	rope = NewRope(text, testSettings)
+	for offset := range text {
+		found, ok := MatchingBracket(rope, offset, DefaultBrackets)
+		expected, expectedOk := naiveMatchingBracket(text, offset, DefaultBrackets)
+		assert(t, found == expected && ok == expectedOk, "Wrong match at", offset, ":", found, ok)
+	}
+}
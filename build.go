@@ -0,0 +1,30 @@
+package rope
+
+// Builds a balanced rope, splitting the value where adjust does, but creating each node once, instead of creating every level
+// as a leaf and then splitting it.
+func buildRope[T any](value []T, settings *Settings) *Rope[T] {
+	if len(value) <= settings.SplitLength {
+		return NewRope(value[:len(value):len(value)], settings)
+	}
+	split := splitIndex(value, settings)
+	node := newNode[T](settings)
+	node.left = buildRope(value[:split:split], settings)
+	node.right = buildRope(value[split:], settings)
+	node.length = len(value)
+	node.updateHeight()
+	return node
+}
+
+// Links the leaves into a balanced tree, which must not be empty.
+// Short leaves are joined as the tree is built.
+func fromLeaves[T any](leaves []*Rope[T], settings *Settings) *Rope[T] {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	node := newNode[T](settings)
+	node.left = fromLeaves(leaves[:len(leaves) / 2], settings)
+	node.right = fromLeaves(leaves[len(leaves) / 2:], settings)
+	node.length = node.left.length + node.right.length
+	node.adjust()
+	return node
+}
@@ -0,0 +1,93 @@
+package rope
+
+import "io"
+
+// Builds a rope from values written in order, filling leaves of
+// SplitLength and linking them at the end, so nothing is copied twice
+type Builder[T any] struct {
+	settings *Settings
+	leaves   []*Rope[T]
+	current  []T // The leaf being filled, with SplitLength capacity
+	length   int
+}
+
+func NewBuilder[T any](settings *Settings) *Builder[T] {
+	if settings == nil {
+		panic(ErrNilSettings)
+	}
+	return &Builder[T]{settings: settings}
+}
+
+// Works like io.Writer, so a byte builder can be used as one. Never fails.
+func (b *Builder[T]) Write(p []T) (n int, err error) {
+	for len(p) > 0 {
+		b.grow()
+		copied := copy(b.current[len(b.current):cap(b.current)], p)
+		b.current = b.current[:len(b.current) + copied]
+		b.length += copied
+		n += copied
+		p = p[copied:]
+	}
+	return n, nil
+}
+
+// Makes room in the current leaf, starting another if it is full
+func (b *Builder[T]) grow() {
+	if len(b.current) < cap(b.current) {
+		return
+	}
+	b.flush()
+	b.current = make([]T, 0, b.settings.SplitLength)
+}
+
+func (b *Builder[T]) flush() {
+	if len(b.current) == 0 {
+		return
+	}
+	leaf := newNode[T](b.settings)
+	leaf.value = b.current[:len(b.current):len(b.current)]
+	leaf.length = len(b.current)
+	b.leaves = append(b.leaves, leaf)
+	b.current = nil
+}
+
+func (b *Builder[T]) Length() int {
+	return b.length
+}
+
+// Returns the rope with everything written so far, balanced.
+// Writing can continue, and doesn't affect the ropes already returned.
+func (b *Builder[T]) Rope() *Rope[T] {
+	b.flush()
+	if len(b.leaves) == 0 {
+		return Empty[T](b.settings)
+	}
+	return fromLeaves(append([]*Rope[T]{}, b.leaves...), b.settings)
+}
+
+// A byte builder, which can also read from streams
+type Writer struct {
+	Builder[byte]
+}
+
+func NewWriter(settings *Settings) *Writer {
+	return &Writer{*NewBuilder[byte](settings)}
+}
+
+// Reads until io.EOF directly into the leaves, so io.Copy doesn't
+// need an intermediate buffer
+func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
+	for {
+		w.grow()
+		read, err := r.Read(w.current[len(w.current):cap(w.current)])
+		w.current = w.current[:len(w.current) + read]
+		w.length += read
+		n += int64(read)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
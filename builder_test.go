@@ -0,0 +1,38 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	builder := NewBuilder[int](testSettings)
+	expected := []int{}
+	for i := 0; i < 20; i++ {
+		builder.Write([]int{i, -i, i})
+		expected = append(expected, i, -i, i)
+	}
+	rope := builder.Rope()
+	assertValue(t, rope, expected)
+	assert(t, builder.Length() == 60 && rope.Validate() == nil, "Invalid rope:", rope.Validate())
+	assert(t, rope.Depth() == rope.IdealDepth(), "Not balanced:", rope.Depth())
+
+	builder.Write([]int{100})
+	assertValue(t, rope, expected)
+	assertValue(t, builder.Rope(), append(expected, 100))
+	assertValue(t, NewBuilder[int](testSettings).Rope(), []int{})
+}
+
+func TestWriter(t *testing.T) {
+	text := strings.Repeat("some streamed text\n", 50)
+	writer := NewWriter(testSettings)
+	var _ io.ReaderFrom = writer
+	writer.Write([]byte("> "))
+	n, err := io.Copy(writer, strings.NewReader(text))
+	assert(t, n == int64(len(text)) && err == nil, "Wrong copy:", n, err)
+	rope := writer.Rope()
+	assert(t, bytes.Equal(rope.Value(), []byte("> " + text)), "Wrong value:", string(rope.Value()))
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+}
@@ -0,0 +1,50 @@
+package rope
+
+import (
+	"bufio"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Returns the rope with the UTF-8 text in the range in upper case
+func ToUpper(r *Rope[byte], start, end int) *Rope[byte] {
+	return mapRunes(r, start, end, unicode.ToUpper)
+}
+
+// Returns the rope with the UTF-8 text in the range in lower case
+func ToLower(r *Rope[byte], start, end int) *Rope[byte] {
+	return mapRunes(r, start, end, unicode.ToLower)
+}
+
+// Returns the rope with the UTF-8 text in the range case folded, so texts
+// differing only in case become the same, using simple folding
+func FoldCase(r *Rope[byte], start, end int) *Rope[byte] {
+	return mapRunes(r, start, end, func(char rune) rune {
+		return unicode.ToLower(unicode.ToUpper(char))
+	})
+}
+
+// Returns the rope with the characters in the range mapped, which may
+// change their length in bytes. The characters are read across leaves,
+// and the runs that change are replaced with a Patch, joined if they are
+// closer than SplitLength, so the leaves far from them are shared.
+// Invalid UTF-8 is kept as it is.
+func mapRunes(r *Rope[byte], start, end int, mapping func(rune) rune) *Rope[byte] {
+	start, end = r.mustRange(start, end)
+	reader := bufio.NewReader(NewSectionReader(r, start, end))
+	patch := Patch[byte]{}
+	for offset := start; ; {
+		char, size, err := reader.ReadRune()
+		if err != nil {
+			break
+		}
+		mapped := mapping(char)
+		if mapped == char || (char == utf8.RuneError && size == 1) {
+			offset += size
+			continue
+		}
+		patch = patch.join(r, Edit[byte]{offset, offset + size, utf8.AppendRune(nil, mapped)})
+		offset += size
+	}
+	return patch.Apply(r)
+}
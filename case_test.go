@@ -0,0 +1,31 @@
+package rope
+
+import "testing"
+
+func TestCase(t *testing.T) {
+	text := "Hello, Wörld! ſ ǅ straße"
+	rope := NewRope([]byte(text), testSettings)
+
+	assertValue(t, ToUpper(rope, 0, rope.Length()), []byte("HELLO, WÖRLD! S Ǆ STRAßE"))
+	assertValue(t, ToLower(rope, 0, rope.Length()), []byte("hello, wörld! ſ ǆ straße"))
+	assertValue(t, FoldCase(rope, 0, rope.Length()), []byte("hello, wörld! s ǆ straße"))
+	assertValue(t, ToUpper(rope, 7, 13), []byte("Hello, WÖRLD! ſ ǅ straße"))
+	assertValue(t, rope, []byte(text))
+
+	invalid := NewRope([]byte("a\xffb"), testSettings)
+	assertValue(t, ToUpper(invalid, 0, 3), []byte("A\xffB"))
+}
+
+func TestCaseShares(t *testing.T) {
+	text := make([]byte, 4000)
+	for i := range text {
+		text[i] = 'A'
+	}
+	text[2000] = 'b'
+	rope := NewRope(text, DefaultSettings)
+	changed := ToUpper(rope, 0, rope.Length())
+
+	assert(t, changed.At(2000) == 'B', "Not changed")
+	assert(t, changed.SharedWith(rope) > 0.5, "Untouched leaves not shared:", changed.SharedWith(rope))
+	assert(t, ToUpper(changed, 0, changed.Length()) == changed, "Unchanged rope not shared")
+}
@@ -0,0 +1,82 @@
+package rope
+
+import (
+	"hash"
+	"hash/crc32"
+)
+
+// Writes the memory of the elements to the hash, leaf by leaf, and
+// returns its sum. Like for Fingerprint, types containing pointers are
+// hashed by where their data is.
+func (r *Rope[T]) Checksum(h hash.Hash) []byte {
+	r.walk(0, r.length, func(chunk []T) bool {
+		h.Write(elementBytes(chunk))
+		return true
+	})
+	return h.Sum(nil)
+}
+
+type crc32Key struct{}
+
+// Returns the IEEE CRC-32 of the memory of the elements, as
+// crc32.ChecksumIEEE, cached on the nodes, so after an edit only the
+// changed path is hashed again, and the rest combined.
+func (r *Rope[T]) CRC32() uint32 {
+	return cachedSummary(r, crc32Key{}, func() uint32 {
+		if r.value != nil {
+			return crc32.ChecksumIEEE(elementBytes(r.value))
+		}
+		var element T
+		rightBytes := int64(r.right.length) * int64(len(elementBytes([]T{element})))
+		return combineCRC32(r.left.CRC32(), r.right.CRC32(), rightBytes)
+	})
+}
+
+// Returns the CRC-32 of the data of crc1 followed by rightBytes bytes
+// with crc2, as zlib's crc32_combine: appending zeros to the first data
+// is a linear operator, applied by squaring the one for a single bit.
+func combineCRC32(crc1, crc2 uint32, rightBytes int64) uint32 {
+	if rightBytes <= 0 {
+		return crc1
+	}
+	var even, odd [32]uint32 // Operators for appending 2^n zero bits
+	odd[0] = crc32.IEEE
+	for n := 1; n < 32; n++ {
+		odd[n] = 1 << (n - 1)
+	}
+	squareGF2(&even, &odd) // 2 bits
+	squareGF2(&odd, &even) // 4 bits
+	for {
+		squareGF2(&even, &odd) // Starting with a byte
+		if rightBytes & 1 != 0 {
+			crc1 = timesGF2(&even, crc1)
+		}
+		if rightBytes >>= 1; rightBytes == 0 {
+			break
+		}
+		squareGF2(&odd, &even)
+		if rightBytes & 1 != 0 {
+			crc1 = timesGF2(&odd, crc1)
+		}
+		if rightBytes >>= 1; rightBytes == 0 {
+			break
+		}
+	}
+	return crc1 ^ crc2
+}
+
+func timesGF2(matrix *[32]uint32, vector uint32) uint32 {
+	sum := uint32(0)
+	for i := 0; vector != 0; i, vector = i + 1, vector >> 1 {
+		if vector & 1 != 0 {
+			sum ^= matrix[i]
+		}
+	}
+	return sum
+}
+
+func squareGF2(square, matrix *[32]uint32) {
+	for n := range matrix {
+		square[n] = timesGF2(matrix, matrix[n])
+	}
+}
@@ -0,0 +1,32 @@
+package rope
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	text := bytes.Repeat([]byte("some text to hash "), 100)
+	rope := NewRope(text, testSettings)
+	sum := sha256.Sum256(text)
+	assert(t, bytes.Equal(rope.Checksum(sha256.New()), sum[:]), "Wrong SHA-256")
+	crc := rope.Checksum(crc32.NewIEEE())
+	assert(t, binary.BigEndian.Uint32(crc) == crc32.ChecksumIEEE(text) && rope.CRC32() == crc32.ChecksumIEEE(text), "Wrong CRC-32")
+}
+
+func TestCRC32(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	rope := NewRope([]byte{}, testSettings)
+	assert(t, rope.CRC32() == 0, "Wrong CRC-32 of an empty rope")
+	for i := 0; i < 200; i++ {
+		index := random.Intn(rope.Length() + 1)
+		rope = rope.Insert(index, bytes.Repeat([]byte{byte(i)}, random.Intn(10)))
+		assert(t, rope.CRC32() == crc32.ChecksumIEEE(rope.Value()), "Wrong CRC-32 after", i, "edits")
+	}
+	ints := NewRope([]int32{1, 2, 3, 4, 5, 6, 7, 8, 9}, testSettings)
+	assert(t, ints.CRC32() == crc32.ChecksumIEEE(elementBytes(ints.Value())), "Wrong CRC-32 of wider elements")
+}
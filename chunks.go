@@ -0,0 +1,98 @@
+package rope
+
+import (
+	"bufio"
+	"io"
+)
+
+// Writes the leaves as a stream of chunks, each one the uvarint size of
+// the encoded elements followed by them, ending with a chunk of size 0.
+func (r *Rope[T]) WriteChunks(w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	var err error
+	done := 0
+	r.walk(0, r.length, func(chunk []T) bool {
+		var encoded []byte
+		encoded, err = encodeElements(chunk)
+		if err == nil {
+			err = writeUvarint(buffered, len(encoded))
+		}
+		if err == nil {
+			_, err = buffered.Write(encoded)
+		}
+		done += len(chunk)
+		progress(r.settings, done, r.length)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeUvarint(buffered, 0); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// Reads the chunks written by WriteChunks, linking them into a balanced
+// tree as they arrive
+func ReadChunks[T any](r io.Reader, settings *Settings) (*Rope[T], error) {
+	buffered := bufio.NewReader(r)
+	leaves := []*Rope[T]{}
+	done := 0
+	for {
+		size, err := readUvarint(buffered)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			break
+		}
+		encoded, err := readSized(buffered, size)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeElements[T](encoded)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, NewRope(values, settings))
+		done += len(values)
+		progress(settings, done, -1)
+	}
+	if len(leaves) == 0 {
+		return NewRope([]T{}, settings), nil
+	}
+	return fromLeaves(leaves, settings), nil
+}
+
+// Returns the values of the leaves, in order, without copying them, so
+// they must not be modified. Their capacity is clipped, so appending to
+// them copies them. Release keeps their arrays out of the Pool.
+func (r *Rope[T]) Leaves() [][]T {
+	leaves := [][]T{}
+	r.walkLeaves(func(leaf *Rope[T]) {
+		leaf.disown()
+		leaves = append(leaves, leaf.value[:leaf.length:leaf.length])
+	})
+	return leaves
+}
+
+// Returns a rope with the chunks one after the other, linked into a
+// balanced tree without concatenating them. The chunks become its leaves,
+// split if they are too long and joined if too short, so they must not be
+// modified after.
+func FromChunks[T any](chunks [][]T, settings *Settings) *Rope[T] {
+	if settings == nil {
+		panic(ErrNilSettings)
+	}
+	leaves := []*Rope[T]{}
+	for _, chunk := range chunks {
+		if len(chunk) > 0 {
+			leaves = append(leaves, NewRope(chunk[:len(chunk):len(chunk)], settings))
+		}
+	}
+	if len(leaves) == 0 {
+		return NewRope([]T{}, settings)
+	}
+	return fromLeaves(leaves, settings)
+}
@@ -0,0 +1,56 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestChunks(t *testing.T) {
+	originalValue := make([]int32, 10000)
+	for i := range originalValue {
+		originalValue[i] = int32(i)
+	}
+	rope := NewRope([]int32{}, DefaultSettings)
+	for i := 0; i < len(originalValue); i += 100 {
+		rope = rope.Insert(rope.Length(), originalValue[i:i + 100])
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(rope.WriteChunks(writer))
+	}()
+	read, err := ReadChunks[int32](reader, DefaultSettings)
+	assert(t, err == nil, "Unexpected error:", err)
+	assertValue(t, read, originalValue)
+	assert(t, read.Validate() == nil, "Invalid rope:", read.Validate())
+	assert(t, read.Depth() < rope.Depth(), "Not rebuilt balanced:", read.Depth(), rope.Depth())
+
+	var empty bytes.Buffer
+	assert(t, NewRope([]int32{}, DefaultSettings).WriteChunks(&empty) == nil, "Couldn't write chunks")
+	read, err = ReadChunks[int32](&empty, DefaultSettings)
+	assert(t, err == nil && read.Length() == 0, "Wrong empty rope:", err)
+
+	_, err = ReadChunks[int32](bytes.NewReader([]byte{5, 1, 0}), DefaultSettings)
+	assert(t, errors.Is(err, io.ErrUnexpectedEOF), "Wrong error:", err)
+	_, err = ReadChunks[int32](bytes.NewReader(binary.AppendUvarint(nil, 1 << 50)), DefaultSettings)
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Corrupted size not rejected:", err)
+}
+
+func TestLeavesFromChunks(t *testing.T) {
+	chunks := [][]byte{[]byte("ab"), {}, []byte("cdefghijk"), []byte("l"), []byte("mn")}
+	rope := FromChunks(chunks, testSettings)
+	assertValue(t, rope, []byte("abcdefghijklmn"))
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+	assert(t, &rope.Leaves()[0][0] == &chunks[0][0], "Chunk copied")
+
+	leaves := rope.Leaves()
+	assert(t, bytes.Equal(bytes.Join(leaves, nil), []byte("abcdefghijklmn")), "Wrong leaves", leaves)
+	for _, leaf := range leaves {
+		assert(t, len(leaf) > 0 && len(leaf) <= testSettings.SplitLength && cap(leaf) == len(leaf), "Wrong leaf", leaf)
+	}
+	assertValue(t, FromChunks(leaves, testSettings), rope.Value())
+	assertValue(t, FromChunks[byte](nil, testSettings), []byte{})
+}
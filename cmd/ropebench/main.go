@@ -0,0 +1,198 @@
+// Command ropebench replays a recorded trace of edits against byte ropes
+// with different settings, and reports the latency percentiles and the
+// allocations of each, to tune the settings for real traffic.
+//
+// The trace is a JSON list of operations:
+//
+//	[{"op": "insert", "index": 0, "text": "hello"}, {"op": "remove", "start": 1, "end": 3}]
+//
+// Usage:
+//
+//	ropebench -trace edits.json -split 64,400,4096 -gap -lazy
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+type Op struct {
+	Op    string `json:"op"` // insert or remove
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// A way of applying the operations, which is either a rope or a slice,
+// as a baseline
+type backend struct {
+	name  string
+	apply func(ops []Op) []time.Duration
+}
+
+type result struct {
+	name      string
+	latencies []time.Duration // Sorted
+	mallocs   uint64
+}
+
+func main() {
+	tracePath := flag.String("trace", "", "JSON trace of the edits, read from stdin if empty")
+	splits := flag.String("split", "64,400,4096", "comma separated SplitLengths to try, JoinLength is half")
+	gapBuffer := flag.Bool("gap", false, "also try each SplitLength with GapBuffer")
+	lazyRemove := flag.Bool("lazy", false, "also try each SplitLength with LazyRemove")
+	baseline := flag.Bool("slice", true, "also replay on a plain slice")
+	flag.Parse()
+
+	input := io.Reader(os.Stdin)
+	if *tracePath != "" {
+		file, err := os.Open(*tracePath)
+		if err != nil {
+			fail(err)
+		}
+		defer file.Close()
+		input = file
+	}
+	ops, err := readTrace(input)
+	if err != nil {
+		fail(err)
+	}
+	backends, err := backendsFor(*splits, *gapBuffer, *lazyRemove, *baseline)
+	if err != nil {
+		fail(err)
+	}
+	results := []result{}
+	for _, backend := range backends {
+		results = append(results, run(backend, ops))
+	}
+	report(os.Stdout, results, len(ops))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "ropebench:", err)
+	os.Exit(1)
+}
+
+func readTrace(r io.Reader) ([]Op, error) {
+	ops := []Op{}
+	if err := json.NewDecoder(r).Decode(&ops); err != nil {
+		return nil, fmt.Errorf("reading trace: %w", err)
+	}
+	for i, op := range ops {
+		if op.Op != "insert" && op.Op != "remove" {
+			return nil, fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+		}
+	}
+	return ops, nil
+}
+
+func backendsFor(splits string, gapBuffer, lazyRemove, baseline bool) ([]backend, error) {
+	backends := []backend{}
+	for _, field := range strings.Split(splits, ",") {
+		splitLength, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || splitLength < 2 {
+			return nil, fmt.Errorf("invalid SplitLength %q", field)
+		}
+		settings := rope.Settings{SplitLength: splitLength, JoinLength: splitLength / 2, Rebalance: 1.5}
+		backends = append(backends, ropeBackend(fmt.Sprintf("rope %d", splitLength), settings))
+		if gapBuffer {
+			gap := settings
+			gap.GapBuffer = true
+			backends = append(backends, ropeBackend(fmt.Sprintf("rope %d gap", splitLength), gap))
+		}
+		if lazyRemove {
+			lazy := settings
+			lazy.LazyRemove = true
+			backends = append(backends, ropeBackend(fmt.Sprintf("rope %d lazy", splitLength), lazy))
+		}
+	}
+	if baseline {
+		backends = append(backends, backend{"slice", replaySlice})
+	}
+	return backends, nil
+}
+
+func ropeBackend(name string, settings rope.Settings) backend {
+	return backend{name, func(ops []Op) []time.Duration {
+		return replayRope(ops, &settings)
+	}}
+}
+
+func replayRope(ops []Op, settings *rope.Settings) []time.Duration {
+	latencies := make([]time.Duration, 0, len(ops))
+	document := rope.Empty[byte](settings)
+	for _, op := range ops {
+		start := time.Now()
+		if op.Op == "insert" {
+			document = document.Insert(op.Index, []byte(op.Text))
+		} else {
+			document = document.Remove(op.Start, op.End)
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	return latencies
+}
+
+func replaySlice(ops []Op) []time.Duration {
+	latencies := make([]time.Duration, 0, len(ops))
+	document := []byte{}
+	for _, op := range ops {
+		start := time.Now()
+		if op.Op == "insert" {
+			document = slices.Insert(document, op.Index, []byte(op.Text)...)
+		} else {
+			document = slices.Delete(document, op.Start, op.End)
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	return latencies
+}
+
+func run(backend backend, ops []Op) result {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	latencies := backend.apply(ops)
+	runtime.ReadMemStats(&after)
+	slices.Sort(latencies)
+	return result{backend.name, latencies, after.Mallocs - before.Mallocs}
+}
+
+// Returns the latency below which the fraction of the operations are
+func percentile(latencies []time.Duration, fraction float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	return latencies[int(fraction * float64(len(latencies) - 1))]
+}
+
+func report(w io.Writer, results []result, ops int) {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "backend\tp50\tp90\tp99\tmax\tallocs/op")
+	for _, result := range results {
+		allocs := 0.0
+		if ops > 0 {
+			allocs = float64(result.mallocs) / float64(ops)
+		}
+		fmt.Fprintf(table, "%s\t%v\t%v\t%v\t%v\t%.1f\n", result.name,
+			percentile(result.latencies, 0.5),
+			percentile(result.latencies, 0.9),
+			percentile(result.latencies, 0.99),
+			percentile(result.latencies, 1),
+			allocs,
+		)
+	}
+	table.Flush()
+}
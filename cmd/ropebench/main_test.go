@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const trace = `[
+	{"op": "insert", "index": 0, "text": "hello world"},
+	{"op": "insert", "index": 5, "text": ","},
+	{"op": "remove", "start": 0, "end": 2}
+]`
+
+func TestReplay(t *testing.T) {
+	ops, err := readTrace(strings.NewReader(trace))
+	if err != nil || len(ops) != 3 {
+		t.Fatal("Wrong trace:", ops, err)
+	}
+	backends, err := backendsFor("4,64", true, true, true)
+	if err != nil || len(backends) != 7 {
+		t.Fatal("Wrong backends:", len(backends), err)
+	}
+	results := []result{}
+	for _, backend := range backends {
+		result := run(backend, ops)
+		if len(result.latencies) != 3 {
+			t.Error("Wrong latencies for", result.name, result.latencies)
+		}
+		results = append(results, result)
+	}
+	output := &bytes.Buffer{}
+	report(output, results, len(ops))
+	if !strings.Contains(output.String(), "rope 4 gap") || strings.Count(output.String(), "\n") != 8 {
+		t.Error("Wrong report:\n" + output.String())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := readTrace(strings.NewReader(`[{"op": "move"}]`)); err == nil {
+		t.Error("Unknown op accepted")
+	}
+	if _, err := backendsFor("4,x", false, false, false); err == nil {
+		t.Error("Invalid SplitLength accepted")
+	}
+	if percentile([]time.Duration{1, 2, 3}, 1) != 3 || percentile(nil, 0.5) != 0 {
+		t.Error("Wrong percentile")
+	}
+}
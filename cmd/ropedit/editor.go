@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+// The state of the editor. As ropes are persistent, undoing is just
+// keeping the previous versions, which share most of their nodes.
+type editor struct {
+	path    string
+	text    *rope.Rope[byte]
+	history []*rope.Rope[byte] // Versions before each edit
+	cursor  int                // Offset in text
+	saved   *rope.Rope[byte]   // Version last saved, to know if it is modified
+}
+
+func open(path string, settings *rope.Settings) (*editor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	text := rope.NewPieceRope(data, settings) // Only split where edited
+	return &editor{path: path, text: text, saved: text}, nil
+}
+
+func (e *editor) edit(changed *rope.Rope[byte]) {
+	e.history = append(e.history, e.text)
+	e.text = changed
+}
+
+func (e *editor) insert(data []byte) {
+	e.edit(e.text.Insert(e.cursor, data))
+	e.cursor += len(data)
+}
+
+// Deletes up to count bytes after the cursor
+func (e *editor) delete(count int) {
+	end := min(e.cursor + count, e.text.Length())
+	if end > e.cursor {
+		e.edit(e.text.Remove(e.cursor, end))
+	}
+}
+
+func (e *editor) undo() bool {
+	if len(e.history) == 0 {
+		return false
+	}
+	e.text = e.history[len(e.history) - 1]
+	e.history = e.history[:len(e.history) - 1]
+	e.cursor = min(e.cursor, e.text.Length())
+	return true
+}
+
+func (e *editor) save() error {
+	file, err := os.Create(e.path)
+	if err != nil {
+		return err
+	}
+	if _, err := rope.WriteRangeTo(file, e.text, 0, e.text.Length()); err != nil {
+		file.Close()
+		return err
+	}
+	e.saved = e.text
+	return file.Close()
+}
+
+func (e *editor) modified() bool {
+	return !e.text.SameAs(e.saved)
+}
+
+// Returns where each line starts
+func (e *editor) lineStarts() []int {
+	starts := []int{0}
+	for offset := range rope.FindAll(e.text, []byte("\n")) {
+		starts = append(starts, offset + 1)
+	}
+	return starts
+}
+
+// Returns the line and column of the cursor, from 0
+func (e *editor) position() (line, column int) {
+	starts := e.lineStarts()
+	line, found := slices.BinarySearch(starts, e.cursor)
+	if !found {
+		line--
+	}
+	return line, e.cursor - starts[line]
+}
+
+// Moves the cursor by bytes, horizontally, or by lines, keeping the column
+func (e *editor) move(offset, lines int) {
+	if lines != 0 {
+		starts := e.lineStarts()
+		line, column := e.position()
+		line = max(0, min(line + lines, len(starts) - 1))
+		end := e.text.Length()
+		if line + 1 < len(starts) {
+			end = starts[line + 1] - 1 // Before the newline
+		}
+		e.cursor = min(starts[line] + column, end)
+	}
+	e.cursor = max(0, min(e.cursor + offset, e.text.Length()))
+}
+
+// Returns the lines around the cursor, with its position marked by a |
+func (e *editor) view(context int) string {
+	starts := e.lineStarts()
+	line, column := e.position()
+	output := &bytes.Buffer{}
+	for i := max(0, line - context); i <= min(line + context, len(starts) - 1); i++ {
+		end := e.text.Length()
+		if i + 1 < len(starts) {
+			end = starts[i + 1] - 1
+		}
+		content := e.text.Slice(starts[i], end)
+		if i == line {
+			content = slices.Concat(content[:column], []byte("|"), content[column:])
+		}
+		fmt.Fprintf(output, "%4d  %s\n", i + 1, content)
+	}
+	return output.String()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+var settings = &rope.Settings{SplitLength: 8, JoinLength: 4, Rebalance: 1.5}
+
+func TestEditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	os.WriteFile(path, []byte("first line\nsecond line\nthird\n"), 0o644)
+	e, err := open(path, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.move(0, 1)
+	e.move(7, 0)
+	if line, column := e.position(); line != 1 || column != 7 {
+		t.Error("Wrong position:", line, column)
+	}
+	e.move(0, 1) // The third line is shorter
+	if line, column := e.position(); line != 2 || column != 5 {
+		t.Error("Wrong position:", line, column)
+	}
+	e.insert([]byte(" line"))
+	e.move(-100, 0)
+	e.delete(6)
+	if string(e.text.Value()) != "line\nsecond line\nthird line\n" {
+		t.Error("Wrong text:", string(e.text.Value()))
+	}
+	if !strings.Contains(e.view(0), "   1  |line") {
+		t.Error("Wrong view:", e.view(0))
+	}
+
+	e.undo()
+	if string(e.text.Value()) != "first line\nsecond line\nthird line\n" || !e.modified() {
+		t.Error("Wrong undo:", string(e.text.Value()))
+	}
+	if err := e.save(); err != nil || e.modified() {
+		t.Error("Not saved:", err)
+	}
+	saved, _ := os.ReadFile(path)
+	if string(saved) != "first line\nsecond line\nthird line\n" {
+		t.Error("Wrong file:", string(saved))
+	}
+}
+
+func TestCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+	e, _ := open(path, settings)
+	input := strings.NewReader("i hello\\nworld\nk\n2x\nq\nu\n3j\nbad\nw\nq\n")
+	output := &bytes.Buffer{}
+	run(e, input, output)
+
+	if string(e.text.Value()) != "hello\nworld" {
+		t.Error("Wrong text:", string(e.text.Value()))
+	}
+	for _, expected := range []string{"? unsaved changes", "? unknown command \"bad\"", "new.txt: 11 bytes"} {
+		if !strings.Contains(output.String(), expected) {
+			t.Error("Missing", expected, "in output:\n" + output.String())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Command ropedit is a tiny line-command editor keeping the file in a
+// byte rope, as example code for the rope package.
+//
+// Usage:
+//
+//	ropedit file
+//
+// Commands, one per line:
+//
+//	h, l     move the cursor a byte left or right, or n bytes with a count, like 5l
+//	k, j     move the cursor a line up or down, or n lines with a count
+//	i text   insert the text at the cursor, with \n for newlines
+//	x        delete a byte after the cursor, or n bytes with a count, like 3x
+//	u        undo the last edit
+//	p        print the lines around the cursor
+//	w        save the file
+//	q        quit, which fails with unsaved changes, unlike q!
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: ropedit file")
+		os.Exit(2)
+	}
+	editor, err := open(os.Args[1], rope.DefaultSettings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ropedit:", err)
+		os.Exit(1)
+	}
+	run(editor, os.Stdin, os.Stdout)
+}
+
+// Runs the commands until q or the end of the input
+func run(e *editor, input io.Reader, output io.Writer) {
+	fmt.Fprint(output, e.view(2))
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		quit, err := command(e, scanner.Text(), output)
+		if err != nil {
+			fmt.Fprintln(output, "?", err)
+		}
+		if quit {
+			return
+		}
+	}
+}
+
+// Runs a command, returning whether to quit
+func command(e *editor, line string, output io.Writer) (quit bool, err error) {
+	if strings.HasPrefix(line, "i ") {
+		e.insert([]byte(strings.ReplaceAll(line[2:], `\n`, "\n")))
+		fmt.Fprint(output, e.view(2))
+		return false, nil
+	}
+	count := 1
+	digits := strings.TrimRight(line, "hjklxupwq!")
+	if digits != "" {
+		if count, err = strconv.Atoi(digits); err != nil {
+			return false, fmt.Errorf("unknown command %q", line)
+		}
+	}
+	switch line[len(digits):] {
+	case "h":
+		e.move(-count, 0)
+	case "l":
+		e.move(count, 0)
+	case "k":
+		e.move(0, -count)
+	case "j":
+		e.move(0, count)
+	case "x":
+		e.delete(count)
+	case "u":
+		if !e.undo() {
+			return false, fmt.Errorf("nothing to undo")
+		}
+	case "p":
+	case "w":
+		if err := e.save(); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(output, "%s: %d bytes\n", e.path, e.text.Length())
+		return false, nil
+	case "q":
+		if e.modified() {
+			return false, fmt.Errorf("unsaved changes, q! to quit anyway")
+		}
+		return true, nil
+	case "q!":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q", line)
+	}
+	fmt.Fprint(output, e.view(2))
+	return false, nil
+}
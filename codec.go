@@ -0,0 +1,90 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidEncoding = errors.New("rope: invalid encoding")
+
+// How the elements are encoded, stored in the first byte
+const (
+	rawEncoding    byte = iota // Bytes, as they are
+	binaryEncoding             // Fixed size elements, with encoding/binary
+	gobEncoding                // Anything else, with encoding/gob
+)
+
+func encodeElements[T any](values []T) ([]byte, error) {
+	if bytesValue, ok := any(values).([]byte); ok {
+		return append([]byte{rawEncoding}, bytesValue...), nil
+	}
+	var buffer bytes.Buffer
+	if binary.Size(values) >= 0 {
+		buffer.WriteByte(binaryEncoding)
+		err := binary.Write(&buffer, binary.LittleEndian, values)
+		return buffer.Bytes(), err
+	}
+	buffer.WriteByte(gobEncoding)
+	err := gob.NewEncoder(&buffer).Encode(values)
+	return buffer.Bytes(), err
+}
+
+func decodeElements[T any](data []byte) ([]T, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no data", ErrInvalidEncoding)
+	}
+	values := []T{}
+	switch data[0] {
+	case rawEncoding:
+		if _, ok := any(values).([]byte); !ok {
+			return nil, fmt.Errorf("%w: bytes decoded into another type", ErrInvalidEncoding)
+		}
+		return any(append([]byte{}, data[1:]...)).([]T), nil
+	case binaryEncoding:
+		var element T
+		size := binary.Size(element)
+		if size <= 0 || (len(data) - 1) % size != 0 {
+			return nil, fmt.Errorf("%w: %d bytes of elements of size %d", ErrInvalidEncoding, len(data) - 1, size)
+		}
+		values = make([]T, (len(data) - 1) / size)
+		err := binary.Read(bytes.NewReader(data[1:]), binary.LittleEndian, values)
+		return values, err
+	case gobEncoding:
+		err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&values)
+		return values, err
+	}
+	return nil, fmt.Errorf("%w: unknown element encoding %d", ErrInvalidEncoding, data[0])
+}
+
+// Marshals the values, with the most compact encoding available
+// for the element type. Settings aren't included.
+func (r *Rope[T]) MarshalBinary() ([]byte, error) {
+	return encodeElements(r.Value())
+}
+
+// Rebuilds the rope balanced, keeping its settings,
+// or using DefaultSettings if it has none
+func (r *Rope[T]) UnmarshalBinary(data []byte) error {
+	if r.frozen {
+		return ErrFrozen
+	}
+	values, err := decodeElements[T](data)
+	if err != nil {
+		return err
+	}
+	settings := r.settings
+	if settings == nil {
+		settings = DefaultSettings
+	}
+	*r = *buildRope(values, settings)
+	return nil
+}
+
+// Registers the rope type with gob, which is needed to send ropes
+// inside interface values
+func RegisterGob[T any]() {
+	gob.Register(&Rope[T]{})
+}
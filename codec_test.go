@@ -0,0 +1,58 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	type point struct {
+		X, Y int32
+	}
+	bytesRope := NewRope([]byte("hello, world"), testSettings)
+	fixedRope := NewRope([]point{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}}, testSettings)
+	gobRope := NewRope([]string{"a", "b", "c", "d", "e", "f"}, testSettings)
+
+	decodedBytes := NewRope([]byte{}, testSettings)
+	data, err := bytesRope.MarshalBinary()
+	assert(t, err == nil && decodedBytes.UnmarshalBinary(data) == nil, "Unexpected error:", err)
+	assertSameValue(t, bytesRope, decodedBytes)
+
+	decodedFixed := &Rope[point]{}
+	data, err = fixedRope.MarshalBinary()
+	assert(t, err == nil && decodedFixed.UnmarshalBinary(data) == nil, "Unexpected error:", err)
+	assert(t, data[0] == binaryEncoding && len(data) == 1 + 5 * 8, "Wrong encoding:", data)
+	assertSameValue(t, fixedRope, decodedFixed)
+
+	decodedGob := NewRope([]string{}, testSettings)
+	data, err = gobRope.MarshalBinary()
+	assert(t, err == nil && decodedGob.UnmarshalBinary(data) == nil, "Unexpected error:", err)
+	assertSameValue(t, gobRope, decodedGob)
+	assert(t, decodedGob.Validate() == nil, "Invalid rope:", decodedGob.Validate())
+
+	err = decodedFixed.UnmarshalBinary([]byte{binaryEncoding, 1, 2, 3})
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Wrong error:", err)
+}
+
+func TestGob(t *testing.T) {
+	RegisterGob[int]()
+	var buffer bytes.Buffer
+	originalValue := make([]int, 1000)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	var sent any = NewRope(originalValue, DefaultSettings)
+
+	err := gob.NewEncoder(&buffer).Encode(&sent)
+	assert(t, err == nil, "Unexpected error:", err)
+	var received any
+	err = gob.NewDecoder(&buffer).Decode(&received)
+	assert(t, err == nil, "Unexpected error:", err)
+
+	rope := received.(*Rope[int])
+	assertValue(t, rope, originalValue)
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+	assert(t, rope.Depth() == rope.IdealDepth(), "Not balanced:", rope.Depth(), rope.IdealDepth())
+}
@@ -0,0 +1,128 @@
+package rope
+
+import "sync/atomic"
+
+// Returns a rope with the runs of adjacent leaves which don't own their
+// backing array, as they were sliced from a longer one, or are shorter
+// than JoinLength, or have spare capacity, packed into leaves of up to
+// SplitLength, each copied into a backing array of its own of their
+// exact size, and linked into a balanced tree. Sliced leaves keep all of
+// the original array alive, and heavy editing leaves many short ones, so
+// a rope can retain much more memory than its length suggests, and be
+// deeper than needed. Leaves owning their array, as allocated by edits,
+// are shared if they are full, and pieces are copied as they are.
+func (r *Rope[T]) Compact() *Rope[T] {
+	leaves := []*Rope[T]{}
+	r.walkLeaves(func(leaf *Rope[T]) {
+		leaves = append(leaves, leaf)
+	})
+	loose := func(leaf *Rope[T]) bool {
+		return !leaf.piece && (atomic.LoadUint32(&leaf.owned) == 0 ||
+			leaf.length < r.settings.JoinLength || cap(leaf.value) > leaf.length)
+	}
+	compacted := []*Rope[T]{}
+	for start := 0; start < len(leaves); {
+		if leaves[start].piece {
+			compacted = append(compacted, leaves[start].Clone())
+			start++
+			continue
+		}
+		end, length := start, 0
+		for end < len(leaves) && loose(leaves[end]) {
+			length += leaves[end].length
+			end++
+		}
+		if end == start || end - start == 1 && cap(leaves[start].value) == leaves[start].length &&
+			atomic.LoadUint32(&leaves[start].owned) == 1 { // Nothing to pack
+			compacted = append(compacted, leaves[start])
+			start++
+			continue
+		}
+		compacted = append(compacted, r.pack(leaves[start:end], length)...)
+		start = end
+	}
+	if len(compacted) == 0 {
+		return NewRope([]T{}, r.settings)
+	}
+	return fromLeaves(compacted, r.settings)
+}
+
+// Copies the elements of the leaves, of the given total length, into
+// leaves of SplitLength and a last shorter one, each with its own array
+func (r *Rope[T]) pack(leaves []*Rope[T], length int) []*Rope[T] {
+	r.countCopied(length)
+	packed := []*Rope[T]{}
+	var current []T
+	for _, leaf := range leaves {
+		for value := leaf.value[:leaf.length]; len(value) > 0; {
+			if current == nil {
+				current = make([]T, 0, min(length, r.settings.SplitLength))
+			}
+			take := min(cap(current) - len(current), len(value))
+			current, value = append(current, value[:take]...), value[take:]
+			length -= take
+			if len(current) == cap(current) {
+				packed = append(packed, ownedLeaf(current, r.settings))
+				current = nil
+			}
+		}
+	}
+	return packed
+}
+
+// Calls visit with the leaves that aren't empty, in order
+func (r *Rope[T]) walkLeaves(visit func(leaf *Rope[T])) {
+	if r.value != nil {
+		if r.length > 0 {
+			visit(r)
+		}
+		return
+	}
+	r.left.walkLeaves(visit)
+	r.right.walkLeaves(visit)
+}
+
+// Returns a deep copy, sharing no nodes or backing arrays with the
+// original, or with the slices it was created from. Pieces are copied
+// as pieces, so it is as cheap to edit as the original.
+func (r *Rope[T]) Clone() *Rope[T] {
+	cloned := newNode[T](r.settings)
+	cloned.length = r.length
+	cloned.piece = r.piece
+	if r.value != nil {
+		cloned.value = make([]T, r.length)
+		copy(cloned.value, r.value)
+		cloned.owned = 1
+		r.countCopied(r.length)
+		return cloned
+	}
+	cloned.left = r.left.Clone()
+	cloned.right = r.right.Clone()
+	cloned.height = r.height
+	return cloned
+}
+
+// Returns a rope with every piece copied into regular leaves, so no
+// work is left for the first edits and nothing references the original
+// of NewPieceRope. Regular leaves are shared with r.
+func (r *Rope[T]) Materialize() *Rope[T] {
+	if r.piece {
+		value := make([]T, r.length)
+		copy(value, r.value)
+		r.countCopied(r.length)
+		return buildRope(value, r.settings)
+	}
+	if r.value != nil {
+		return r
+	}
+	left, right := r.left.Materialize(), r.right.Materialize()
+	if left == r.left && right == r.right {
+		return r
+	}
+	materialized := newNode[T](r.settings)
+	materialized.length = r.length
+	materialized.left = left
+	materialized.right = right
+	materialized.updateHeight()
+	return materialized
+}
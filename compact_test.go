@@ -0,0 +1,113 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+func leavesOf[T any](rope *Rope[T]) [][]T {
+	if rope.value != nil {
+		return [][]T{rope.value}
+	}
+	return append(leavesOf(rope.left), leavesOf(rope.right)...)
+}
+
+func TestCompact(t *testing.T) {
+	originalValue := make([]int, 64, 128)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	rope := NewRope(originalValue, testSettings)
+	compacted := rope.Compact()
+
+	assertSameValue(t, rope, compacted)
+	assert(t, maxDepth(rope) == maxDepth(compacted), "Compact changed the full leaves")
+	for _, leaf := range leavesOf(compacted) {
+		assert(t, cap(leaf) == len(leaf), "Leaf wasn't trimmed:", len(leaf), cap(leaf))
+		assert(t, &leaf[0] != &originalValue[leaf[0]], "Leaf still shares the original array")
+	}
+	recompacted := compacted.Compact()
+	assert(t, &leavesOf(recompacted)[0][0] == &leavesOf(compacted)[0][0], "Owned full leaf copied")
+
+	sub := rope.SubRope(10, 50)
+	compacted = sub.Compact()
+	assertSameValue(t, sub, compacted)
+	for _, leaf := range leavesOf(compacted) {
+		assert(t, &leaf[0] != &originalValue[leaf[0]], "Leaf of a sub-rope still shares the original array")
+	}
+
+	appended := Empty[int](testSettings).Append([]int{1, 2, 3})
+	assert(t, cap(appended.value) > appended.length, "No spare capacity to trim")
+	compacted = appended.Compact()
+	assertSameValue(t, appended, compacted)
+	assert(t, cap(compacted.value) == compacted.length, "Leaf wasn't trimmed:", cap(compacted.value))
+}
+
+func TestCompactFragmented(t *testing.T) {
+	settings := *testSettings
+	settings.SplitLength, settings.JoinLength = 8, 4
+	value := make([]int, 80)
+	for i := range value {
+		value[i] = i
+	}
+	rope := NewRope(value, &settings) // Leaves of 5
+	edits := []Edit[int]{}
+	expected := []int{}
+	for start := 0; start < len(value); start += 5 {
+		if start == 35 { // Copied by the edit into a full leaf owning its array
+			edits = append(edits, Edit[int]{start, start + 5, value[start:start + 5]})
+			expected = append(expected, value[start:start + 5]...)
+			continue
+		}
+		edits = append(edits, Edit[int]{start + 1, start + 4, nil})
+		expected = append(expected, value[start], value[start + 4])
+	}
+	rope = rope.ReplaceRanges(edits)
+	before := rope.FragmentationStats()
+	compacted := rope.Compact()
+	after := compacted.FragmentationStats()
+
+	assertValue(t, compacted, expected)
+	assert(t, compacted.Validate() == nil, "Invalid rope:", compacted.Validate())
+	assert(t, after.Ratio < before.Ratio && after.SpareBytes == 0, "Leaves not merged:", before, after)
+	lengths := []int{}
+	for _, leaf := range leavesOf(compacted) {
+		lengths = append(lengths, len(leaf))
+		assert(t, cap(leaf) == len(leaf), "Leaf wasn't trimmed:", len(leaf), cap(leaf))
+	}
+	assert(t, slices.Equal(lengths, []int{8, 6, 5, 8, 8}), "Wrong leaves:", lengths)
+	full, _ := compacted.leafAt(15)
+	edited, _ := rope.leafAt(15)
+	assert(t, full == edited, "Full leaf copied")
+	assert(t, compacted.Depth() <= rope.Depth(), "Deeper after compacting")
+	assert(t, Empty[int](&settings).Compact().Length() == 0, "Empty rope not compacted")
+}
+
+func TestClone(t *testing.T) {
+	original := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	rope := NewPieceRope(original, testSettings).Insert(5, []int{-1})
+	cloned := rope.Clone()
+	original[0] = 100
+
+	assertValue(t, cloned, []int{0, 1, 2, 3, 4, -1, 5, 6, 7, 8, 9})
+	assert(t, ShareStats(rope, cloned).Nodes == 0, "Clone shares nodes")
+	for _, leaf := range leavesOf(cloned) {
+		assert(t, &leaf[0] != &original[0], "Leaf still shares the original array")
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	original := make([]int, 64)
+	for i := range original {
+		original[i] = i
+	}
+	rope := NewPieceRope(original, testSettings).Insert(0, []int{-1})
+	materialized := rope.Materialize()
+
+	assertSameValue(t, rope, materialized)
+	assert(t, materialized.Validate() == nil, "Invalid rope:", materialized.Validate())
+	stats := materialized.Stats()
+	assert(t, stats.MaxLeaf <= testSettings.SplitLength, "Pieces left:", stats.MaxLeaf)
+	regular := NewRope(original, testSettings)
+	assert(t, regular.Materialize() == regular, "Regular rope copied")
+}
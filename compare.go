@@ -0,0 +1,46 @@
+package rope
+
+import "cmp"
+
+// Like comparing Value() to s, but walking the leaves without
+// allocating, and stopping at the first difference. Elements are
+// compared as set in Settings.Eq.
+func EqualSlice[T any](r *Rope[T], s []T) bool {
+	if r.length != len(s) {
+		return false
+	}
+	equal := equalFunc[T](r.settings)
+	offset := 0
+	return r.walk(0, r.length, func(chunk []T) bool {
+		for i, value := range chunk {
+			if !equal(value, s[offset + i]) {
+				return false
+			}
+		}
+		offset += len(chunk)
+		return true
+	})
+}
+
+// Compares the values lexicographically to s, like slices.Compare
+// on Value(), without allocating
+func CompareSlice[T cmp.Ordered](r *Rope[T], s []T) int {
+	result, offset := 0, 0
+	r.walk(0, r.length, func(chunk []T) bool {
+		for i, value := range chunk {
+			if offset + i == len(s) {
+				result = 1 // s is a prefix of the rope
+				return false
+			}
+			if result = cmp.Compare(value, s[offset + i]); result != 0 {
+				return false
+			}
+		}
+		offset += len(chunk)
+		return true
+	})
+	if result == 0 && r.length < len(s) {
+		return -1
+	}
+	return result
+}
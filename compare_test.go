@@ -0,0 +1,21 @@
+package rope
+
+import "testing"
+
+func TestEqualSlice(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	assert(t, EqualSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 7}), "Equal slice")
+	assert(t, !EqualSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 8}), "Different last value")
+	assert(t, !EqualSlice(rope, []int{0, 1, 2}), "Different length")
+	assert(t, EqualSlice(Empty[int](testSettings), nil), "Empty rope")
+}
+
+func TestCompareSlice(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	assert(t, CompareSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 7}) == 0, "Equal slice")
+	assert(t, CompareSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 8}) == -1, "Greater slice")
+	assert(t, CompareSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 6}) == 1, "Lesser slice")
+	assert(t, CompareSlice(rope, []int{0, 1, 2}) == 1, "Prefix")
+	assert(t, CompareSlice(rope, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}) == -1, "Longer slice")
+	assert(t, CompareSlice(Empty[int](testSettings), nil) == 0, "Empty rope")
+}
@@ -0,0 +1,50 @@
+package rope
+
+// Reads from a rope, remembering the last leaf it accessed, so
+// sequential reads don't descend from the root every time.
+// Unlike ropes, cursors aren't safe for concurrent use.
+type Cursor[T any] struct {
+	rope      *Rope[T]
+	leaf      *Rope[T]
+	leafStart int
+}
+
+func (r *Rope[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{rope: r}
+}
+
+func (c *Cursor[T]) Rope() *Rope[T] {
+	return c.rope
+}
+
+func (c *Cursor[T]) At(index int) T {
+	index = c.rope.mustIndex(index, c.rope.length - 1)
+	return c.at(index)
+}
+
+// Like At, without checking the index
+func (c *Cursor[T]) at(index int) T {
+	c.seek(index)
+	return c.leaf.value[index - c.leafStart]
+}
+
+// Like Rope.CopySlice
+func (c *Cursor[T]) CopySlice(dst []T, start, end int) int {
+	start, end = c.rope.mustRange(start, end)
+	end = min(end, start + len(dst))
+	if start == end {
+		return 0
+	}
+	c.seek(start)
+	if end <= c.leafStart + c.leaf.length {
+		return copy(dst, c.leaf.value[start - c.leafStart:end - c.leafStart])
+	}
+	c.rope.copySlice(dst, start, end)
+	return end - start
+}
+
+func (c *Cursor[T]) seek(index int) {
+	if c.leaf == nil || index < c.leafStart || index >= c.leafStart + c.leaf.length {
+		c.leaf, c.leafStart = c.rope.leafAt(index)
+	}
+}
@@ -0,0 +1,27 @@
+package rope
+
+import "testing"
+
+func TestCursor(t *testing.T) {
+	originalValue := make([]int, 100)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	rope := NewRope(originalValue, testSettings).Insert(50, []int{-1, -2})
+	expected := rope.Value()
+	cursor := rope.Cursor()
+
+	for i := range expected {
+		assert(t, cursor.At(i) == expected[i], "Wrong value at", i)
+		assert(t, rope.At(i) == expected[i], "Wrong value at", i)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		assert(t, cursor.At(i) == expected[i], "Wrong value at", i)
+	}
+
+	slice := make([]int, 10)
+	for i := 0; i < len(expected) - 10; i += 3 {
+		cursor.CopySlice(slice, i, i + 10)
+		assertValue(t, NewRope(slice, testSettings), expected[i:i + 10])
+	}
+}
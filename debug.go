@@ -0,0 +1,40 @@
+package rope
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultPreviewLength = 8 // Elements shown for each leaf when debugging
+
+// Prints the tree indented, so %#v is readable
+func (r *Rope[T]) GoString() string {
+	return r.DebugString(defaultPreviewLength)
+}
+
+// Prints the tree indented, with the lengths of the nodes and
+// up to maxLeafPreview elements of each leaf.
+func (r *Rope[T]) DebugString(maxLeafPreview int) string {
+	var builder strings.Builder
+	r.debugString(&builder, maxLeafPreview, 0)
+	return builder.String()
+}
+
+func (r *Rope[T]) debugString(builder *strings.Builder, maxLeafPreview, depth int) {
+	builder.WriteString(strings.Repeat("  ", depth))
+	if r.value != nil {
+		fmt.Fprintf(builder, "Leaf(%d) %s\n", r.length, r.preview(maxLeafPreview))
+		return
+	}
+	fmt.Fprintf(builder, "Rope(%d)\n", r.length)
+	r.left.debugString(builder, maxLeafPreview, depth + 1)
+	r.right.debugString(builder, maxLeafPreview, depth + 1)
+}
+
+// Formats the first elements of a leaf, marking if there are more
+func (r *Rope[T]) preview(maxLength int) string {
+	if len(r.value) > maxLength {
+		return fmt.Sprintf("%v...", r.value[:maxLength])
+	}
+	return fmt.Sprintf("%v", r.value)
+}
@@ -0,0 +1,18 @@
+package rope
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDebugString(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings).Insert(1, []int{-1})
+	expected := "Rope(9)\n" +
+		"  Rope(5)\n" +
+		"    Leaf(2) [0 -1]\n" +
+		"    Leaf(3) [1 2]...\n" +
+		"  Leaf(4) [4 5]...\n"
+
+	assert(t, rope.DebugString(2) == expected, "Wrong debug string:\n" + rope.DebugString(2))
+	assert(t, fmt.Sprintf("%#v", rope) == rope.DebugString(defaultPreviewLength), "GoString not used")
+}
@@ -0,0 +1,145 @@
+package rope
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// A delta has the magic and format version, followed by the operations
+// building the new rope, and an end marker:
+//
+//	magic, version byte
+//	  copy:    0, uvarint start, uvarint length, from the old rope
+//	  literal: 1, uvarint size, encoded elements
+//	end: 2
+const (
+	deltaMagic   = "ROPD"
+	deltaVersion = 1
+	deltaCopy    = 0
+	deltaLiteral = 1
+	deltaEnd     = 2
+)
+
+// Writes what is needed to build new from old: subtrees of new with the
+// same content as one in old are sent as their offset in old, and only
+// the other leaves are sent in full.
+func EncodeDelta[T any](old, new *Rope[T], w io.Writer) error {
+	offsets := map[snapshotKey]int{}
+	old.indexOffsets(offsets, 0)
+
+	buffered := bufio.NewWriter(w)
+	buffered.WriteString(deltaMagic)
+	buffered.WriteByte(deltaVersion)
+	if err := new.encodeDelta(offsets, buffered, 0, new.length); err != nil {
+		return err
+	}
+	buffered.WriteByte(deltaEnd)
+	return buffered.Flush()
+}
+
+func (r *Rope[T]) indexOffsets(offsets map[snapshotKey]int, offset int) {
+	if r.length == 0 {
+		return
+	}
+	key := snapshotKey{r.fingerprint(), r.length}
+	if _, ok := offsets[key]; !ok {
+		offsets[key] = offset
+	}
+	if r.value == nil {
+		r.left.indexOffsets(offsets, offset)
+		r.right.indexOffsets(offsets, offset + r.left.length)
+	}
+}
+
+// The position and total are only used for reporting progress
+func (r *Rope[T]) encodeDelta(offsets map[snapshotKey]int, w *bufio.Writer, position, total int) error {
+	if r.length == 0 {
+		return nil
+	}
+	if offset, ok := offsets[snapshotKey{r.fingerprint(), r.length}]; ok {
+		w.WriteByte(deltaCopy)
+		writeUvarint(w, offset)
+		progress(r.settings, position + r.length, total)
+		return writeUvarint(w, r.length)
+	}
+	if r.value != nil {
+		encoded, err := encodeElements(r.value)
+		if err != nil {
+			return err
+		}
+		w.WriteByte(deltaLiteral)
+		writeUvarint(w, len(encoded))
+		_, err = w.Write(encoded)
+		progress(r.settings, position + r.length, total)
+		return err
+	}
+	if err := r.left.encodeDelta(offsets, w, position, total); err != nil {
+		return err
+	}
+	return r.right.encodeDelta(offsets, w, position + r.left.length, total)
+}
+
+// Builds the new rope from the old one and the delta written by EncodeDelta.
+// The copied parts reference the leaves of old instead of copying them.
+func ApplyDelta[T any](old *Rope[T], r io.Reader) (*Rope[T], error) {
+	buffered := bufio.NewReader(r)
+	header := make([]byte, len(deltaMagic) + 1)
+	if _, err := io.ReadFull(buffered, header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(deltaMagic)]) != deltaMagic || header[len(deltaMagic)] != deltaVersion {
+		return nil, fmt.Errorf("%w: not a delta", ErrInvalidEncoding)
+	}
+
+	leaves := []*Rope[T]{}
+	addLeaf := func(value []T) {
+		leaves = append(leaves, NewRope(value, old.settings))
+	}
+	for {
+		kind, err := buffered.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case deltaCopy:
+			start, err := readUvarint(buffered)
+			if err != nil {
+				return nil, err
+			}
+			length, err := readUvarint(buffered)
+			if err != nil {
+				return nil, err
+			}
+			if start + length > old.length || start + length < start {
+				return nil, fmt.Errorf("%w: copy of [%d, %d) with length %d",
+					ErrInvalidEncoding, start, start + length, old.length)
+			}
+			old.subRope(start, start + length).walkLeaves(func(leaf *Rope[T]) {
+				leaf.disown() // Its array is now viewed by the new rope too
+				addLeaf(leaf.value[:leaf.length:leaf.length])
+			})
+		case deltaLiteral:
+			size, err := readUvarint(buffered)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := readSized(buffered, size)
+			if err != nil {
+				return nil, err
+			}
+			values, err := decodeElements[T](encoded)
+			if err != nil {
+				return nil, err
+			}
+			addLeaf(values)
+		case deltaEnd:
+			if len(leaves) == 0 {
+				return NewRope([]T{}, old.settings), nil
+			}
+			return fromLeaves(leaves, old.settings), nil
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %d", ErrInvalidEncoding, kind)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestDelta(t *testing.T) {
+	originalValue := make([]int, 10000)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	old := NewRope(originalValue, DefaultSettings)
+	new := old.Insert(5000, []int{-1, -2}).Remove(100, 150).Replace(9000, []int{-3})
+
+	var delta, full bytes.Buffer
+	assert(t, EncodeDelta(old, new, &delta) == nil, "Couldn't encode delta")
+	assert(t, EncodeDelta(NewRope([]int{}, DefaultSettings), new, &full) == nil, "Couldn't encode delta")
+	assert(t, delta.Len() * 4 < full.Len(), "Delta too large:", delta.Len(), full.Len())
+
+	received := NewRope(originalValue, testSettings) // Same content, with another shape
+	applied, err := ApplyDelta(received, &delta)
+	assert(t, err == nil, "Unexpected error:", err)
+	assertSameValue(t, applied, new)
+	assert(t, applied.Validate() == nil, "Invalid rope:", applied.Validate())
+
+	applied, err = ApplyDelta(NewRope([]int{}, DefaultSettings), &full)
+	assert(t, err == nil, "Unexpected error:", err)
+	assertSameValue(t, applied, new)
+
+	corrupted := binary.AppendUvarint([]byte("ROPD\x01\x01"), 1 << 50)
+	_, err = ApplyDelta(old, bytes.NewReader(corrupted))
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Corrupted size not rejected:", err)
+}
@@ -0,0 +1,315 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+)
+
+const (
+	diffWindow = 1 << 16 // Bytes of each side kept to find where they match again
+	diffAnchor = 16      // Bytes that must match to take the sides as matching again
+	diffRead   = 4096    // Minimum bytes read from the source at once
+)
+
+// Returns the edits turning the rope into the content of src, reading it
+// once, while keeping at most a window of it in memory besides the
+// inserted bytes. After the sides differ, the edit ends where the next
+// diffAnchor bytes of both match again, within diffWindow bytes, or is
+// made of the whole window if they don't, so edits are small, but not
+// always minimal, when the content changed a lot. An empty patch means
+// the content is the same.
+func DiffReader(r *Rope[byte], src io.Reader) (Patch[byte], error) {
+	source := &diffSource{reader: src}
+	patch := Patch[byte]{}
+	position := 0
+	for {
+		if err := source.fill(diffWindow); err != nil {
+			return nil, err
+		}
+		same := commonPrefix(r, position, source.buffer)
+		position += same
+		source.buffer = source.buffer[same:]
+		if len(source.buffer) == 0 {
+			if !source.eof {
+				continue
+			}
+			if position < r.length {
+				patch = patch.add(Edit[byte]{position, r.length, nil})
+			}
+			return patch, nil
+		}
+		// The sides differ at position
+		rest := min(r.length - position, diffWindow)
+		window := r.Slice(position, position + rest)
+		if source.eof && position + rest == r.length { // Both ends are known
+			suffix := commonSuffix(window, source.buffer)
+			inserted := bytes.Clone(source.buffer[:len(source.buffer) - suffix])
+			return patch.add(Edit[byte]{position, r.length - suffix, inserted}), nil
+		}
+		removed, inserted := resync(window, source.buffer)
+		patch = patch.add(Edit[byte]{position, position + removed, bytes.Clone(source.buffer[:inserted])})
+		position += removed
+		source.buffer = source.buffer[inserted:]
+	}
+}
+
+// Reads ahead from the source of DiffReader
+type diffSource struct {
+	reader io.Reader
+	buffer []byte
+	eof    bool
+}
+
+// Reads until there are n bytes buffered, or the source ends
+func (s *diffSource) fill(n int) error {
+	for len(s.buffer) < n && !s.eof {
+		if cap(s.buffer) - len(s.buffer) < diffRead {
+			grown := make([]byte, len(s.buffer), max(n, 2 * len(s.buffer)) + diffRead)
+			copy(grown, s.buffer)
+			s.buffer = grown
+		}
+		read, err := s.reader.Read(s.buffer[len(s.buffer):cap(s.buffer)])
+		s.buffer = s.buffer[:len(s.buffer) + read]
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns how many values from start are the same as the first of values
+func commonPrefix[T comparable](r *Rope[T], start int, values []T) int {
+	same := 0
+	r.walk(start, min(r.length, start + len(values)), func(chunk []T) bool {
+		for _, value := range chunk {
+			if value != values[same] {
+				return false
+			}
+			same++
+		}
+		return true
+	})
+	return same
+}
+
+func commonSuffix(a, b []byte) int {
+	same := 0
+	for same < len(a) && same < len(b) && a[len(a) - 1 - same] == b[len(b) - 1 - same] {
+		same++
+	}
+	return same
+}
+
+// Returns how many bytes of old and new to replace so they start with the
+// same diffAnchor bytes, skipping as few bytes of new as possible, or
+// both whole if there are none
+func resync(old, new []byte) (removed, inserted int) {
+	anchors := map[string]int{}
+	for i := 0; i + diffAnchor <= len(old); i++ {
+		if _, ok := anchors[string(old[i:i + diffAnchor])]; !ok {
+			anchors[string(old[i:i + diffAnchor])] = i
+		}
+	}
+	for i := 0; i + diffAnchor <= len(new); i++ {
+		if j, ok := anchors[string(new[i:i + diffAnchor])]; ok {
+			return j, i
+		}
+	}
+	return len(old), len(new)
+}
+
+// Appends the edit, merging it with the last one if they touch
+func (p Patch[T]) add(edit Edit[T]) Patch[T] {
+	if len(p) > 0 && p[len(p) - 1].End == edit.Start {
+		last := &p[len(p) - 1]
+		last.End = edit.End
+		last.With = append(last.With, edit.With...)
+		return p
+	}
+	return append(p, edit)
+}
+
+// Beyond this many edited elements, a region is replaced as a whole
+const diffMaxCost = 512
+
+// Returns the edits turning old into new. Subtrees of new with the same
+// content as one in old, further than the last one matched, are skipped,
+// and the regions left between them are diffed concurrently, so it is
+// fast for versions of the same rope, or built from the same leaves.
+// The edits are minimal within each region, unless it needs more than
+// diffMaxCost edited elements, in which case it is replaced whole.
+// Elements are compared as set in the Settings.Eq of old, and progress is
+// reported to its OnProgress as the regions are done.
+func Diff[T any](old, new *Rope[T]) Patch[T] {
+	equal := equalFunc[T](old.settings)
+	offsets := map[snapshotKey][]int{}
+	old.indexShared(offsets, 0)
+	matches := []diffMatch{}
+	oldPosition := 0
+	new.matchShared(offsets, 0, &oldPosition, &matches)
+	matches = append(matches, diffMatch{old.length, new.length, 0})
+
+	patches := make([]Patch[T], len(matches))
+	var wait sync.WaitGroup
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+
+	// Progress is reported in elements of new, the matched ones done from the start
+	var reporting sync.Mutex
+	done, reported := 0, -1
+	for _, match := range matches {
+		done += match.length
+	}
+	report := func(region int) {
+		reporting.Lock()
+		defer reporting.Unlock()
+		done += region
+		if done > reported {
+			reported = done
+			progress(old.settings, done, new.length)
+		}
+	}
+
+	oldStart, newStart := 0, 0
+	for i, match := range matches {
+		if oldStart < match.old || newStart < match.new {
+			wait.Add(1)
+			go func(oldStart, newStart int) {
+				defer wait.Done()
+				workers <- struct{}{}
+				defer func() { <-workers }()
+				patches[i] = diffSlices(old.Slice(oldStart, match.old), new.Slice(newStart, match.new), oldStart, equal)
+				report(match.new - newStart)
+			}(oldStart, newStart)
+		}
+		oldStart, newStart = match.old + match.length, match.new + match.length
+	}
+	wait.Wait()
+	report(0) // If no region was left
+
+	patch := Patch[T]{}
+	for _, region := range patches {
+		for _, edit := range region {
+			patch = patch.add(edit)
+		}
+	}
+	return patch
+}
+
+// A run of the same elements at old and new
+type diffMatch struct {
+	old, new, length int
+}
+
+// Indexes where the subtrees long enough to be matched start
+func (r *Rope[T]) indexShared(offsets map[snapshotKey][]int, offset int) {
+	if r.length < diffAnchor {
+		return
+	}
+	key := snapshotKey{r.fingerprint(), r.length}
+	offsets[key] = append(offsets[key], offset) // The offsets are visited in order
+	if r.value == nil {
+		r.left.indexShared(offsets, offset)
+		r.right.indexShared(offsets, offset + r.left.length)
+	}
+}
+
+// Adds the subtrees also in old, from the first after oldPosition
+func (r *Rope[T]) matchShared(offsets map[snapshotKey][]int, offset int, oldPosition *int, matches *[]diffMatch) {
+	if r.length < diffAnchor {
+		return
+	}
+	candidates := offsets[snapshotKey{r.fingerprint(), r.length}]
+	if i := sort.SearchInts(candidates, *oldPosition); i < len(candidates) {
+		*matches = append(*matches, diffMatch{candidates[i], offset, r.length})
+		*oldPosition = candidates[i] + r.length
+		return
+	}
+	if r.value == nil {
+		r.left.matchShared(offsets, offset, oldPosition, matches)
+		r.right.matchShared(offsets, offset + r.left.length, oldPosition, matches)
+	}
+}
+
+// Returns the minimal edits turning old into new, found with Myers'
+// algorithm after trimming the common ends, with offset added to them
+func diffSlices[T any](old, new []T, offset int, equal func(a, b T) bool) Patch[T] {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && equal(old[prefix], new[prefix]) {
+		prefix++
+	}
+	old, new, offset = old[prefix:], new[prefix:], offset + prefix
+	suffix := 0
+	for suffix < len(old) && suffix < len(new) && equal(old[len(old) - 1 - suffix], new[len(new) - 1 - suffix]) {
+		suffix++
+	}
+	old, new = old[:len(old) - suffix], new[:len(new) - suffix]
+	replaced := Patch[T]{{offset, offset + len(old), slices.Clone(new)}}
+	if len(old) == 0 || len(new) == 0 {
+		if len(old) == 0 && len(new) == 0 {
+			return nil
+		}
+		return replaced
+	}
+
+	// furthest[limit + k] is the furthest x reached on the diagonal x - y = k
+	limit := min(len(old) + len(new), diffMaxCost)
+	furthest := make([]int, 2 * limit + 2)
+	trace := [][]int{}
+	for cost := 0; cost <= limit; cost++ {
+		trace = append(trace, slices.Clone(furthest))
+		for k := -cost; k <= cost; k += 2 {
+			var x int
+			if k == -cost || (k != cost && furthest[limit + k - 1] < furthest[limit + k + 1]) {
+				x = furthest[limit + k + 1] // From an insertion
+			} else {
+				x = furthest[limit + k - 1] + 1 // From a removal
+			}
+			y := x - k
+			for x < len(old) && y < len(new) && equal(old[x], new[y]) {
+				x, y = x + 1, y + 1
+			}
+			furthest[limit + k] = x
+			if x >= len(old) && y >= len(new) {
+				return backtrack(trace, limit, old, new, offset)
+			}
+		}
+	}
+	return replaced
+}
+
+// Follows the furthest points back from the ends, turning each step
+// into a single element edit, merged with the ones it touches
+func backtrack[T any](trace [][]int, limit int, old, new []T, offset int) Patch[T] {
+	edits := []Edit[T]{}
+	x, y := len(old), len(new)
+	for cost := len(trace) - 1; cost > 0; cost-- {
+		furthest := trace[cost]
+		k := x - y
+		previous := k - 1
+		if k == -cost || (k != cost && furthest[limit + k - 1] < furthest[limit + k + 1]) {
+			previous = k + 1
+		}
+		previousX := furthest[limit + previous]
+		previousY := previousX - previous
+		for x > previousX && y > previousY { // Same elements
+			x, y = x - 1, y - 1
+		}
+		if x == previousX {
+			edits = append(edits, Edit[T]{offset + x, offset + x, []T{new[previousY]}})
+		} else {
+			edits = append(edits, Edit[T]{offset + previousX, offset + x, nil})
+		}
+		x, y = previousX, previousY
+	}
+	patch := Patch[T]{}
+	for i := len(edits) - 1; i >= 0; i-- {
+		patch = patch.add(edits[i])
+	}
+	return patch
+}
@@ -0,0 +1,100 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDiffReader(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	original := make([]byte, 3 * diffWindow)
+	for i := range original {
+		original[i] = byte('a' + random.Intn(26))
+	}
+	rope := NewRope(original, DefaultSettings)
+
+	cases := map[string][]byte{
+		"same":     original,
+		"empty":    {},
+		"inserted": append(append(bytes.Clone(original[:1000]), "inserted"...), original[1000:]...),
+		"removed":  append(bytes.Clone(original[:1000]), original[5000:]...),
+		"replaced": append(append(bytes.Clone(original[:diffWindow]), "replaced"...), original[diffWindow + 3:]...),
+		"appended": append(bytes.Clone(original), "appended"...),
+		"truncated": original[:len(original) - 10],
+		"rewritten": bytes.Repeat([]byte("x"), 2 * diffWindow + 5),
+	}
+	for name, content := range cases {
+		patch, err := DiffReader(rope, iotest.HalfReader(bytes.NewReader(content)))
+		assert(t, err == nil, name, "failed:", err)
+		assert(t, patch.Validate(rope.Length()) == nil, name, "gave an invalid patch:", patch.Validate(rope.Length()))
+		assertValue(t, patch.Apply(rope), content)
+		if name != "rewritten" {
+			assert(t, len(patch) <= 1, name, "gave too many edits:", len(patch))
+		}
+	}
+	patch, _ := DiffReader(rope, bytes.NewReader(cases["inserted"]))
+	assert(t, patch[0].Start == 1000 && patch[0].End == 1000 && string(patch[0].With) == "inserted", "Wrong edit:", patch[0])
+
+	failing := io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(io.ErrUnexpectedEOF))
+	_, err := DiffReader(rope, failing)
+	assert(t, errors.Is(err, io.ErrUnexpectedEOF), "Error not returned:", err)
+}
+
+func TestDiff(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	old := NewRope(make([]int, 5000), DefaultSettings)
+	for i := 0; i < 100; i++ {
+		old = old.Insert(random.Intn(old.Length()), []int{i, i + 1, i + 2})
+	}
+	new := old
+	for i := 0; i < 20; i++ {
+		index := random.Intn(new.Length() - 10)
+		new = new.Remove(index, index + random.Intn(10)).Insert(index, []int{-i, -i})
+	}
+	patch := Diff(old, new)
+	assert(t, patch.Validate(old.Length()) == nil, "Invalid patch:", patch.Validate(old.Length()))
+	assertSameValue(t, patch.Apply(old), new)
+	minimal := diffSlices(old.Value(), new.Value(), 0, equalFunc[int](old.settings))
+	assert(t, diffCost(patch) == diffCost(minimal), "Edits aren't minimal:", diffCost(patch), diffCost(minimal))
+	assert(t, len(Diff(old, old)) == 0, "Edits between the same rope")
+	assert(t, len(Diff(old, NewRope(old.Value(), DefaultSettings))) == 0, "Edits between the same content")
+
+	small := Diff(NewRope([]int{1, 2, 3, 4, 5}, testSettings), NewRope([]int{1, 3, 4, 6, 5, 7}, testSettings))
+	assert(t, len(small) == 3, "Edits aren't minimal:", small)
+	assertValue(t, small.Apply(NewRope([]int{1, 2, 3, 4, 5}, testSettings)), []int{1, 3, 4, 6, 5, 7})
+
+	unrelated := NewRope(make([]int, 3 * diffMaxCost), testSettings)
+	rewritten := Diff(unrelated, NewRope(slices.Repeat([]int{1}, 2 * diffMaxCost), testSettings))
+	assert(t, len(rewritten) == 1 && rewritten[0].End == unrelated.Length(), "Not replaced whole:", len(rewritten))
+}
+
+func TestDiffSlices(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		random := rand.New(rand.NewSource(int64(i)))
+		old, new := make([]int, random.Intn(30)), make([]int, random.Intn(30))
+		for j := range old {
+			old[j] = random.Intn(4)
+		}
+		for j := range new {
+			new[j] = random.Intn(4)
+		}
+		patch := diffSlices(old, new, 0, equalFunc[int](testSettings))
+		assert(t, patch.Validate(len(old)) == nil, "Invalid patch:", patch.Validate(len(old)))
+		assertValue(t, patch.Apply(NewRope(old, testSettings)), new)
+	}
+}
+
+// Returns how many elements the patch removes and inserts
+func diffCost[T any](patch Patch[T]) int {
+	cost := 0
+	for _, edit := range patch {
+		cost += edit.End - edit.Start + len(edit.With)
+	}
+	return cost
+}
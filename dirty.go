@@ -0,0 +1,65 @@
+package rope
+
+// Makes the current version the one DirtyRanges compares to, as after
+// saving it. Documents start clean.
+func (d *Document[T]) MarkClean() {
+	d.clean = d.Rope()
+}
+
+// Returns the ranges of the current version which changed since the last
+// MarkClean, sorted and not touching. The nodes of the current version
+// not in the clean one are changed, so ranges are made of whole leaves,
+// and are empty where only elements were removed. Edits share most nodes,
+// but rebalancing or compacting the rope can make all of it changed.
+// The nodes of both versions are visited, but not their elements.
+func (d *Document[T]) DirtyRanges() []Range {
+	offsets := map[*Rope[T]]int{}
+	d.clean.indexNodes(offsets, 0)
+	ranges := []Range{}
+	expected := 0 // Where the next shared node is in the clean version
+	d.Rope().findDirty(offsets, 0, &expected, &ranges)
+	if expected != d.clean.length {
+		ranges = addDirty(ranges, d.Rope().length, d.Rope().length)
+	}
+	return ranges
+}
+
+// Adds where the nodes are, first where they appear more than once
+func (r *Rope[T]) indexNodes(offsets map[*Rope[T]]int, offset int) {
+	if _, ok := offsets[r]; ok {
+		return
+	}
+	offsets[r] = offset
+	if r.value == nil {
+		r.left.indexNodes(offsets, offset)
+		r.right.indexNodes(offsets, offset + r.left.length)
+	}
+}
+
+// Adds the leaves not in offsets, and where the nodes in it don't follow
+// the end of the previous one, expected, in the clean version
+func (r *Rope[T]) findDirty(offsets map[*Rope[T]]int, offset int, expected *int, ranges *[]Range) {
+	if clean, ok := offsets[r]; ok {
+		if clean != *expected { // Elements were removed before it
+			*ranges = addDirty(*ranges, offset, offset)
+		}
+		*expected = clean + r.length
+		return
+	}
+	if r.value != nil {
+		*ranges = addDirty(*ranges, offset, offset + r.length)
+		*expected = -1
+		return
+	}
+	r.left.findDirty(offsets, offset, expected, ranges)
+	r.right.findDirty(offsets, offset + r.left.length, expected, ranges)
+}
+
+// Appends the range, merging it with the last one if they touch
+func addDirty(ranges []Range, start, end int) []Range {
+	if len(ranges) > 0 && ranges[len(ranges) - 1].End >= start {
+		ranges[len(ranges) - 1].End = max(ranges[len(ranges) - 1].End, end)
+		return ranges
+	}
+	return append(ranges, Range{start, end})
+}
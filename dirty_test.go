@@ -0,0 +1,31 @@
+package rope
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDirtyRanges(t *testing.T) {
+	document := NewDocument(NewRope(bytes.Repeat([]byte("abcdefgh"), 10000), DefaultSettings))
+	assert(t, len(document.DirtyRanges()) == 0, "New document dirty")
+
+	document.Apply(Edit[byte]{40000, 40000, []byte("inserted")})
+	document.Apply(Edit[byte]{70000, 70100, nil})
+	ranges := document.DirtyRanges()
+	assert(t, len(ranges) == 2, "Wrong dirty ranges", ranges)
+	assert(t, ranges[0].Start <= 40000 && ranges[0].End >= 40008 && ranges[1].Start <= 70000 && ranges[1].End >= 70000,
+		"Edits not in the dirty ranges", ranges)
+	size := 0
+	for _, r := range ranges {
+		size += r.End - r.Start
+	}
+	assert(t, size <= 4 * DefaultSettings.SplitLength, "Unchanged leaves dirty", ranges)
+
+	document.MarkClean()
+	assert(t, len(document.DirtyRanges()) == 0, "Document dirty after MarkClean")
+	first, _ := document.Rope().leafAt(0)
+	document.Apply(Edit[byte]{0, first.length, nil}) // Removes a whole leaf
+	ranges = document.DirtyRanges()
+	assert(t, len(ranges) == 1 && ranges[0].Start == 0 && ranges[0].End < DefaultSettings.SplitLength,
+		"Removal not in the dirty ranges", ranges)
+}
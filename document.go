@@ -0,0 +1,151 @@
+package rope
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Identifies a version of a Document, in the order they were made
+type VersionID int
+
+type version[T any] struct {
+	rope   *Rope[T]
+	parent VersionID // The version it was edited from, -1 for the first
+}
+
+// A rope edited through Edits, keeping every version it had, which is
+// cheap as they share most of their nodes. It isn't safe for concurrent
+// use, though the ropes it returns are.
+type Document[T any] struct {
+	versions []version[T]
+	current  VersionID
+	wal      *WAL[T]
+	tags     map[string]VersionID
+	base     *Rope[T] // For forks, what was last merged from them
+	clean    *Rope[T] // As of the last MarkClean
+	saved    savedFile
+}
+
+func NewDocument[T any](rope *Rope[T]) *Document[T] {
+	return &Document[T]{versions: []version[T]{{rope, -1}}, clean: rope}
+}
+
+// Returns the rope at the current version
+func (d *Document[T]) Rope() *Rope[T] {
+	return d.versions[d.current].rope
+}
+
+func (d *Document[T]) Version() VersionID {
+	return d.current
+}
+
+// Applies the edit to the current version, logging it to the WAL first
+// if there is one, and returns the new version. If the edit is out of
+// range, or can't be logged, the document isn't changed.
+func (d *Document[T]) Apply(edit Edit[T]) (VersionID, error) {
+	if err := (Patch[T]{edit}).Validate(d.Rope().length); err != nil {
+		return d.current, err
+	}
+	return d.commit(d.Rope().Remove(edit.Start, edit.End).Insert(edit.Start, edit.With), edit)
+}
+
+// Makes the rope, the result of applying the edits in order, the next version
+func (d *Document[T]) commit(rope *Rope[T], edits ...Edit[T]) (VersionID, error) {
+	for _, edit := range edits {
+		if d.wal == nil {
+			break
+		}
+		if err := d.wal.Append(edit); err != nil {
+			return d.current, err
+		}
+	}
+	d.versions = append(d.versions, version[T]{rope, d.current})
+	d.current = VersionID(len(d.versions) - 1)
+	return d.current, nil
+}
+
+// Makes a new version with the content of an earlier one, sharing its
+// nodes. It is logged to the WAL as replacing the whole content.
+func (d *Document[T]) Revert(version VersionID) (VersionID, error) {
+	rope := d.RopeAt(version)
+	return d.commit(rope, Edit[T]{0, d.Rope().length, rope.Value()})
+}
+
+// Names the version, like "saved", replacing the version with the name
+// if there was one. Panics with ErrUnknownVersion if there is no version.
+func (d *Document[T]) Tag(version VersionID, name string) {
+	d.RopeAt(version) // Checks it
+	if d.tags == nil {
+		d.tags = map[string]VersionID{}
+	}
+	d.tags[name] = version
+}
+
+// Returns the version with the name, and whether there was one
+func (d *Document[T]) Tagged(name string) (VersionID, bool) {
+	version, ok := d.tags[name]
+	return version, ok
+}
+
+func (d *Document[T]) Untag(name string) {
+	delete(d.tags, name)
+}
+
+// Returns the rope at the version, which is as cheap as at the current
+// one. Panics with ErrUnknownVersion if the document never had it.
+func (d *Document[T]) RopeAt(version VersionID) *Rope[T] {
+	if version < 0 || int(version) >= len(d.versions) {
+		panic(fmt.Errorf("%w: %d", ErrUnknownVersion, version))
+	}
+	return d.versions[version].rope
+}
+
+func (d *Document[T]) LengthAt(version VersionID) int {
+	return d.RopeAt(version).length
+}
+
+// Returns the range at the version, without restoring it
+func (d *Document[T]) SliceAt(version VersionID, start, end int) []T {
+	return d.RopeAt(version).Slice(start, end)
+}
+
+// Logs the edits applied from now on to the WAL, or stops if it is nil
+func (d *Document[T]) SetWAL(wal *WAL[T]) {
+	d.wal = wal
+}
+
+// Returns an independent branch of the document, sharing its versions
+// up to the current one, and its tags, but not its WAL. The versions made
+// after forking have the same IDs in both, but aren't the same.
+func (d *Document[T]) Fork() *Document[T] {
+	return &Document[T]{
+		versions: slices.Clip(d.versions), // So appending to either copies them
+		current:  d.current,
+		tags:     maps.Clone(d.tags),
+		base:     d.Rope(),
+		clean:    d.clean,
+	}
+}
+
+// Makes a new version of the document with the changes made in the
+// branch since it was forked, or last merged, combined as by Merge3.
+// The edits from the current version are logged to the WAL one by one,
+// from the last, so replaying them makes a version for each.
+func MergeBranch[T any](d, branch *Document[T]) (VersionID, error) {
+	if branch.base == nil {
+		return d.current, fmt.Errorf("%w: not a fork", ErrUnknownVersion)
+	}
+	patch, err := mergePatches(Diff(branch.base, d.Rope()), Diff(branch.base, branch.Rope()), equalFunc[T](branch.base.settings))
+	if err != nil {
+		return d.current, err
+	}
+	merged := patch.Apply(branch.base)
+	logged := Diff(d.Rope(), merged)
+	slices.Reverse(logged) // So the offsets stay valid when replaying them
+	version, err := d.commit(merged, logged...)
+	if err == nil {
+		branch.base = branch.Rope()
+	}
+	return version, err
+}
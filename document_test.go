@@ -0,0 +1,96 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	original := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	document := NewDocument(original)
+	assert(t, document.Version() == 0 && document.Rope() == original, "Wrong first version")
+
+	version, err := document.Apply(Edit[int]{2, 4, []int{-1}})
+	assert(t, err == nil && version == 1 && document.Version() == 1, "Wrong version:", version, err)
+	assertValue(t, document.Rope(), []int{0, 1, -1, 4, 5, 6, 7})
+	assertValue(t, original, []int{0, 1, 2, 3, 4, 5, 6, 7})
+
+	version, err = document.Apply(Edit[int]{5, 9, nil})
+	assert(t, errors.Is(err, ErrIndexOutOfRange) && version == 1, "Edit out of range applied:", version, err)
+	assertValue(t, document.Rope(), []int{0, 1, -1, 4, 5, 6, 7})
+}
+
+func TestSliceAt(t *testing.T) {
+	document := NewDocument(NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings))
+	first := document.Version()
+	document.Apply(Edit[int]{0, 4, nil})
+	second, _ := document.Apply(Edit[int]{0, 0, []int{-1, -2}})
+
+	assert(t, document.LengthAt(first) == 8 && document.LengthAt(second) == 6, "Wrong lengths")
+	assertValue(t, NewRope(document.SliceAt(first, 2, 6), testSettings), []int{2, 3, 4, 5})
+	assertValue(t, NewRope(document.SliceAt(1, 0, 4), testSettings), []int{4, 5, 6, 7})
+	assertValue(t, NewRope(document.SliceAt(second, 1, 3), testSettings), []int{-2, 4})
+	assertPanics(t, ErrUnknownVersion, func() { document.SliceAt(3, 0, 0) })
+	assertPanics(t, ErrIndexOutOfRange, func() { document.SliceAt(second, 0, 8) })
+}
+
+func TestTag(t *testing.T) {
+	var log bytes.Buffer
+	document := NewDocument(NewRope([]int{0, 1, 2, 3}, testSettings))
+	document.SetWAL(NewWAL[int](&log))
+	document.Tag(document.Version(), "saved")
+	document.Apply(Edit[int]{0, 2, []int{-1}})
+	document.Tag(document.Version(), "edited")
+
+	saved, ok := document.Tagged("saved")
+	assert(t, ok && saved == 0, "Wrong tag:", saved, ok)
+	_, ok = document.Tagged("missing")
+	assert(t, !ok, "Missing tag found")
+	document.Untag("edited")
+	_, ok = document.Tagged("edited")
+	assert(t, !ok, "Tag not removed")
+	assertPanics(t, ErrUnknownVersion, func() { document.Tag(5, "future") })
+
+	reverted, err := document.Revert(saved)
+	assert(t, err == nil && reverted == 2, "Wrong version:", reverted, err)
+	assert(t, document.Rope() == document.RopeAt(saved), "Reverted version not shared")
+	replayed := NewDocument(NewRope([]int{0, 1, 2, 3}, testSettings))
+	ReplayWAL(replayed, &log)
+	assertValue(t, replayed.Rope(), []int{0, 1, 2, 3})
+}
+
+func TestFork(t *testing.T) {
+	var log bytes.Buffer
+	document := NewDocument(NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings))
+	document.Tag(0, "base")
+	branch := document.Fork()
+	document.SetWAL(NewWAL[int](&log))
+
+	document.Apply(Edit[int]{0, 1, []int{-1}})
+	branch.Apply(Edit[int]{6, 8, nil})
+	branch.Apply(Edit[int]{3, 3, []int{30}})
+	assertValue(t, document.Rope(), []int{-1, 1, 2, 3, 4, 5, 6, 7})
+	assertValue(t, branch.Rope(), []int{0, 1, 2, 30, 3, 4, 5})
+	_, ok := branch.Tagged("base")
+	assert(t, ok && branch.RopeAt(0) == document.RopeAt(0), "Versions not shared")
+
+	version, err := MergeBranch(document, branch)
+	assert(t, err == nil && version == 2, "Merge failed:", version, err)
+	assertValue(t, document.Rope(), []int{-1, 1, 2, 30, 3, 4, 5})
+	branch.Apply(Edit[int]{7, 7, []int{-2}})
+	_, err = MergeBranch(document, branch)
+	assert(t, err == nil, "Merge failed:", err)
+	assertValue(t, document.Rope(), []int{-1, 1, 2, 30, 3, 4, 5, -2})
+
+	replayed := NewDocument(NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings))
+	ReplayWAL(replayed, &log)
+	assertSameValue(t, replayed.Rope(), document.Rope())
+
+	branch.Apply(Edit[int]{2, 3, []int{-3}})
+	document.Apply(Edit[int]{2, 3, []int{-4}})
+	_, err = MergeBranch(document, branch)
+	assert(t, errors.Is(err, ErrConflict), "Conflict not found:", err)
+	_, err = MergeBranch(document, document)
+	assert(t, errors.Is(err, ErrUnknownVersion), "Merged a document that isn't a fork:", err)
+}
@@ -0,0 +1,55 @@
+package rope
+
+import (
+	"fmt"
+	"io"
+)
+
+// Writes the structure of the rope in the Graphviz DOT format,
+// with nodes named after their addresses.
+func (r *Rope[T]) DumpDOT(w io.Writer) error {
+	return DumpVersionsDOT(w, r)
+}
+
+// Writes many ropes into a single DOT graph, so nodes shared between
+// versions show up once, with an edge from each rope that uses them.
+func DumpVersionsDOT[T any](w io.Writer, ropes ...*Rope[T]) error {
+	dot := &dotWriter{w: w}
+	dot.printf("digraph rope {\n\tnode [shape=box];\n")
+	written := map[*Rope[T]]bool{}
+	for i, rope := range ropes {
+		dot.printf("\tversion%d [shape=ellipse, label=\"version %d\"];\n", i, i)
+		dot.printf("\tversion%d -> \"%p\";\n", i, rope)
+		rope.dumpDOT(dot, written)
+	}
+	dot.printf("}\n")
+	return dot.err
+}
+
+func (r *Rope[T]) dumpDOT(dot *dotWriter, written map[*Rope[T]]bool) {
+	if written[r] {
+		return
+	}
+	written[r] = true
+	if r.value != nil {
+		label := fmt.Sprintf("%d: %s", r.length, r.preview(defaultPreviewLength))
+		dot.printf("\t\"%p\" [label=%q];\n", r, label)
+		return
+	}
+	dot.printf("\t\"%p\" [shape=circle, label=\"%d\"];\n", r, r.length)
+	dot.printf("\t\"%p\" -> \"%p\";\n\t\"%p\" -> \"%p\";\n", r, r.left, r, r.right)
+	r.left.dumpDOT(dot, written)
+	r.right.dumpDOT(dot, written)
+}
+
+// Keeps the first error, so the output can be written without checking each time
+type dotWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (d *dotWriter) printf(format string, args ...any) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, format, args...)
+	}
+}
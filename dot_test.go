@@ -0,0 +1,20 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpDOT(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	newRope := rope.Insert(0, []int{-1})
+
+	var builder strings.Builder
+	err := DumpVersionsDOT(&builder, rope, newRope)
+	dot := builder.String()
+
+	assert(t, err == nil, "Unexpected error:", err)
+	assert(t, strings.HasPrefix(dot, "digraph rope {"), "Not a digraph:", dot)
+	assert(t, strings.Count(dot, "label=\"4: [4 5 6 7]\"") == 1, "Shared leaf must appear once:", dot)
+	assert(t, strings.Count(dot, "->") == 2 + 2 + 4, "Wrong number of edges:", dot)
+}
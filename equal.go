@@ -0,0 +1,20 @@
+package rope
+
+import "reflect"
+
+// Returns the Eq of the settings, or == if T is comparable, or
+// reflect.DeepEqual if it isn't, or is an interface, whose values may
+// not be comparable
+func equalFunc[T any](settings *Settings) func(a, b T) bool {
+	if settings.Eq != nil {
+		return settings.Eq.(func(a, b T) bool)
+	}
+	if element := reflect.TypeFor[T](); element.Comparable() && element.Kind() != reflect.Interface {
+		return func(a, b T) bool {
+			return any(a) == any(b)
+		}
+	}
+	return func(a, b T) bool {
+		return reflect.DeepEqual(a, b)
+	}
+}
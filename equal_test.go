@@ -0,0 +1,33 @@
+package rope
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type taggedWord struct {
+	word string
+	tags []string
+}
+
+func TestEqualDeep(t *testing.T) {
+	words := []taggedWord{{"a", nil}, {"b", []string{"x"}}, {"a", nil}, {"b", []string{"x"}}}
+	rope := NewRope(words, testSettings)
+	assert(t, EqualSlice(rope, slices.Clone(words)), "Equal elements with slices differ")
+	assert(t, Count(rope, []taggedWord{{"b", []string{"x"}}}) == 2, "Wrong count of deeply equal elements")
+	edited := rope.Insert(2, []taggedWord{{"c", nil}})
+	assert(t, len(Diff(rope, edited)) == 1, "Wrong diff of deeply equal elements", Diff(rope, edited))
+}
+
+func TestEqualSettings(t *testing.T) {
+	settings := *testSettings
+	settings.Eq = func(a, b string) bool { return strings.EqualFold(a, b) }
+	rope := NewRope([]string{"Go", "is", "GO", "go"}, &settings)
+	assert(t, slices.Equal(slices.Collect(FindAll(rope, []string{"go"})), []int{0, 2, 3}), "Eq not used by FindAll")
+	assert(t, EqualSlice(rope, []string{"go", "IS", "go", "go"}), "Eq not used by EqualSlice")
+	assert(t, Count(NewRope([]string{"Go", "is"}, testSettings), []string{"go"}) == 0, "Eq used without being set")
+	changed := NewRope([]string{"GO", "IS", "go", "Go", "!"}, &settings)
+	patch := Diff(rope, changed)
+	assert(t, len(patch) == 1 && patch[0].Start == 4 && patch[0].End == 4, "Eq not used by Diff", patch)
+}
@@ -0,0 +1,166 @@
+package rope
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Invalid indices are programmer errors, so the regular methods panic
+// with these errors, while the Try methods check first and return them.
+var (
+	ErrIndexOutOfRange = errors.New("rope: index out of range")
+	ErrInvalidRange    = errors.New("rope: range start after its end")
+	ErrNilSettings     = errors.New("rope: nil settings")
+	ErrTooLong         = errors.New("rope: length would overflow int")
+	ErrInvalidRope     = errors.New("rope: invariant violated")
+	ErrFrozen          = errors.New("rope: modifying a frozen rope")
+	ErrUnknownVersion  = errors.New("rope: unknown document version")
+)
+
+// What to do with indices out of range, in Settings.OutOfRange
+type RangePolicy int
+
+const (
+	// The regular methods panic, and the Try methods return an error
+	PanicOutOfRange RangePolicy = iota
+	// Indices are moved into range, and ranges with the start after
+	// the end become empty, so neither the regular nor Try methods fail
+	ClampOutOfRange
+)
+
+// Checks 0 <= index <= limit, where index == limit is valid
+// so insertions can be done at the end.
+func (r *Rope[T]) checkIndex(index, limit int) (int, error) {
+	index = r.fromEnd(index)
+	if index >= 0 && index <= limit {
+		return index, nil
+	}
+	if r.settings.OutOfRange == ClampOutOfRange && limit >= 0 {
+		if index < 0 {
+			return 0, nil
+		}
+		return limit, nil
+	}
+	return index, fmt.Errorf("%w: %d with length %d", ErrIndexOutOfRange, index, r.length)
+}
+
+// Checks 0 <= start <= end <= length. Empty ranges are valid anywhere in
+// the rope, and reversed ones are errors, or empty at start when clamping.
+func (r *Rope[T]) checkRange(start, end int) (int, int, error) {
+	start, end = r.fromEnd(start), r.fromEnd(end)
+	if start > end {
+		if r.settings.OutOfRange != ClampOutOfRange {
+			return start, end, fmt.Errorf("%w: [%d, %d)", ErrInvalidRange, start, end)
+		}
+		end = start
+	}
+	if start >= 0 && end <= r.length {
+		return start, end, nil
+	}
+	if r.settings.OutOfRange != ClampOutOfRange {
+		return start, end, fmt.Errorf("%w: [%d, %d) with length %d", ErrIndexOutOfRange, start, end, r.length)
+	}
+	start, end = bound(start, end, r.length)
+	return start, end, nil
+}
+
+// Checks the replacement fits, and cuts it down to what fits when clamping
+func (r *Rope[T]) checkReplacement(index int, replacement []T) (int, []T, error) {
+	index, err := r.checkIndex(index, r.length)
+	if err != nil {
+		return index, replacement, err
+	}
+	if index + len(replacement) <= r.length {
+		return index, replacement, nil
+	}
+	if r.settings.OutOfRange != ClampOutOfRange {
+		return index, replacement, fmt.Errorf("%w: replacing [%d, %d) with length %d",
+			ErrIndexOutOfRange, index, index + len(replacement), r.length)
+	}
+	return index, replacement[:r.length - index], nil
+}
+
+// Checks the length can grow by that many elements without overflowing
+func (r *Rope[T]) checkGrowth(elements int) error {
+	if elements > math.MaxInt - r.length {
+		return fmt.Errorf("%w: adding %d to %d", ErrTooLong, elements, r.length)
+	}
+	return nil
+}
+
+// Negative indices count from the end if enabled in the settings
+func (r *Rope[T]) fromEnd(index int) int {
+	if index < 0 && r.settings.FromEnd {
+		return index + r.length
+	}
+	return index
+}
+
+func (r *Rope[T]) mustIndex(index, limit int) int {
+	index, err := r.checkIndex(index, limit)
+	must(err)
+	return index
+}
+
+func (r *Rope[T]) mustRange(start, end int) (int, int) {
+	start, end, err := r.checkRange(start, end)
+	must(err)
+	return start, end
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TryNewRope[T any](value []T, settings *Settings) (*Rope[T], error) {
+	if settings == nil {
+		return nil, ErrNilSettings
+	}
+	return NewRope(value, settings), nil
+}
+
+func (r *Rope[T]) TryInsert(index int, insertion []T) (*Rope[T], error) {
+	index, err := r.checkIndex(index, r.length)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkGrowth(len(insertion)); err != nil {
+		return nil, err
+	}
+	return r.Insert(index, insertion), nil
+}
+
+func (r *Rope[T]) TryRemove(start, end int) (*Rope[T], error) {
+	start, end, err := r.checkRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return r.Remove(start, end), nil
+}
+
+func (r *Rope[T]) TryReplace(index int, replacement []T) (*Rope[T], error) {
+	index, replacement, err := r.checkReplacement(index, replacement)
+	if err != nil {
+		return nil, err
+	}
+	return r.Replace(index, replacement), nil
+}
+
+func (r *Rope[T]) TrySlice(start, end int) ([]T, error) {
+	start, end, err := r.checkRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return r.Slice(start, end), nil
+}
+
+func (r *Rope[T]) TryAt(index int) (value T, err error) {
+	index, err = r.checkIndex(index, r.length - 1)
+	if err != nil {
+		return value, err
+	}
+	return r.At(index), nil
+}
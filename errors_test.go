@@ -0,0 +1,98 @@
+package rope
+
+import (
+	"errors"
+	"testing"
+)
+
+func assertPanics(t *testing.T, target error, f func()) {
+	defer func() {
+		err, _ := recover().(error)
+		assert(t, errors.Is(err, target), "Expected panic with", target, "got", err)
+	}()
+	f()
+}
+
+func TestErrors(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+
+	_, err := rope.TryInsert(9, []int{0})
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	_, err = rope.TryRemove(5, 2)
+	assert(t, errors.Is(err, ErrInvalidRange), "Wrong error:", err)
+	_, err = rope.TryReplace(6, []int{0, 0, 0})
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	_, err = rope.TrySlice(-1, 2)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	_, err = rope.TryAt(8)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	_, err = TryNewRope([]int{}, nil)
+	assert(t, errors.Is(err, ErrNilSettings), "Wrong error:", err)
+
+	newRope, err := rope.TryInsert(8, []int{8})
+	assert(t, err == nil, "Unexpected error:", err)
+	assertValue(t, newRope, []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+
+	assertPanics(t, ErrIndexOutOfRange, func() { rope.Insert(9, []int{0}) })
+	assertPanics(t, ErrIndexOutOfRange, func() { rope.Remove(6, 9) })
+	assertPanics(t, ErrInvalidRange, func() { rope.Slice(5, 2) })
+	assertPanics(t, ErrNilSettings, func() { NewRope([]int{}, nil) })
+}
+
+func TestClampOutOfRange(t *testing.T) {
+	settings := *testSettings
+	settings.OutOfRange = ClampOutOfRange
+	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	rope := NewRope(originalValue, &settings)
+
+	assertValue(t, rope.Insert(20, []int{8}), []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+	assertValue(t, rope.Insert(-5, []int{-1}), []int{-1, 0, 1, 2, 3, 4, 5, 6, 7})
+	assertValue(t, rope.Remove(6, 20), []int{0, 1, 2, 3, 4, 5})
+	assertValue(t, rope.Remove(5, 2), originalValue)
+	assertValue(t, rope.Replace(6, []int{-6, -7, -8}), []int{0, 1, 2, 3, 4, 5, -6, -7})
+	assertValue(t, NewRope(rope.Slice(-3, 3), &settings), []int{0, 1, 2})
+	assert(t, rope.At(100) == 7, "Wrong clamped value:", rope.At(100))
+
+	_, err := rope.TryRemove(-1, 100)
+	assert(t, err == nil, "Unexpected error:", err)
+}
+
+func TestFromEnd(t *testing.T) {
+	settings := *testSettings
+	settings.FromEnd = true
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+
+	assertValue(t, rope.Insert(rope.Length(), []int{8}), []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+	assertValue(t, rope.Insert(-1, []int{-1}), []int{0, 1, 2, 3, 4, 5, 6, -1, 7})
+	assertValue(t, rope.Remove(-3, -1), []int{0, 1, 2, 3, 4, 7})
+	assertValue(t, rope.Replace(-2, []int{-6, -7}), []int{0, 1, 2, 3, 4, 5, -6, -7})
+	assert(t, rope.At(-1) == 7, "Wrong value from the end:", rope.At(-1))
+	values := rope.GetMany([]int{-1, 2, -8, 6, -3})
+	assertValue(t, NewRope(values, testSettings), []int{7, 2, 0, 6, 5})
+	assertPanics(t, ErrIndexOutOfRange, func() { rope.At(-9) })
+
+	assertPanics(t, ErrIndexOutOfRange, func() { NewRope([]int{0}, testSettings).At(-1) })
+}
+
+func TestRanges(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	for _, start := range []int{0, 3, 4, 8} {
+		assert(t, len(rope.Slice(start, start)) == 0, "Empty range not empty at", start)
+		assert(t, rope.Remove(start, start) == rope, "Empty removal changed the rope at", start)
+	}
+	assertPanics(t, ErrInvalidRange, func() { rope.Slice(5, 2) })
+	assertPanics(t, ErrInvalidRange, func() { rope.CopySlice(nil, 5, 2) })
+	assertPanics(t, ErrInvalidRange, func() { rope.Remove(5, 2) })
+
+	short := make([]int, 3)
+	assert(t, rope.CopySlice(short, 2, 8) == 3, "Wrong count copied")
+	assert(t, short[0] == 2 && short[2] == 4, "Wrong values copied:", short)
+	assert(t, rope.CopySlice(make([]int, 10), 2, 8) == 6, "Wrong count copied")
+	assert(t, rope.Cursor().CopySlice(short, 3, 8) == 3 && short[2] == 5, "Wrong cursor copy:", short)
+
+	settings := *testSettings
+	settings.OutOfRange = ClampOutOfRange
+	clamped := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	assert(t, len(clamped.Slice(5, 2)) == 0, "Reversed range not empty")
+	assertValue(t, clamped.Remove(5, 2), []int{0, 1, 2, 3, 4, 5, 6, 7})
+}
@@ -0,0 +1,102 @@
+package rope
+
+import "iter"
+
+// Yields the offsets of the matches of the pattern, in order and without
+// overlapping, like strings.Index called repeatedly. Matches may span
+// leaves, and the rope is only scanned as far as the iteration goes.
+// An empty pattern matches at every offset. Elements are compared as set
+// in Settings.Eq.
+func FindAll[T any](r *Rope[T], pattern []T) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(pattern) == 0 {
+			for offset := 0; offset <= r.length; offset++ {
+				if !yield(offset) {
+					return
+				}
+			}
+			return
+		}
+		equal := equalFunc[T](r.settings)
+		failure := failureTable(pattern, equal)
+		matched, offset := 0, 0
+		r.walk(0, r.length, func(chunk []T) bool {
+			for _, value := range chunk {
+				offset++
+				for matched > 0 && !equal(value, pattern[matched]) {
+					matched = failure[matched - 1]
+				}
+				if equal(value, pattern[matched]) {
+					matched++
+				}
+				if matched == len(pattern) {
+					if !yield(offset - len(pattern)) {
+						return false
+					}
+					matched = 0 // Matches don't overlap
+				}
+			}
+			return true
+		})
+	}
+}
+
+// The Knuth-Morris-Pratt table, where failure[i] is the length of the
+// longest proper prefix of pattern[:i + 1] that is also a suffix of it
+func failureTable[T any](pattern []T, equal func(a, b T) bool) []int {
+	failure := make([]int, len(pattern))
+	length := 0
+	for i := 1; i < len(pattern); i++ {
+		for length > 0 && !equal(pattern[i], pattern[length]) {
+			length = failure[length - 1]
+		}
+		if equal(pattern[i], pattern[length]) {
+			length++
+		}
+		failure[i] = length
+	}
+	return failure
+}
+
+// A match of FindAllWithContext, with the lines around it
+type ContextMatch struct {
+	Range         // Of the match
+	Line    int   // Of the start of the match
+	Context Range // From the start of the first line around it to the end of the last, without its line break
+	First   int   // Line of the start of Context
+}
+
+// Yields the matches of the pattern like FindAll, with up to beforeLines
+// lines before the line the match starts in and afterLines lines after
+// the one it ends in, negative counts taken as 0, found from the line breaks counted on the nodes, so
+// search results can be shown without scanning the lines again.
+func FindAllWithContext(r *Rope[byte], pattern []byte, beforeLines, afterLines int) iter.Seq[ContextMatch] {
+	return func(yield func(ContextMatch) bool) {
+		lines := nodeWidthSummary(r).newlines
+		for offset := range FindAll(r, pattern) {
+			end := offset + len(pattern)
+			line := LineAt(r, offset)
+			first := max(line - max(beforeLines, 0), 0)
+			// A match ending in a line break doesn't take the next line in
+			last := min(LineAt(r, max(end - 1, offset)) + max(afterLines, 0), lines)
+			match := ContextMatch{
+				Range:   Range{offset, end},
+				Line:    line,
+				Context: Range{LineStart(r, first), lineEnd(r, last)},
+				First:   first,
+			}
+			if !yield(match) {
+				return
+			}
+		}
+	}
+}
+
+// Returns the number of matches FindAll yields, like strings.Count
+func Count[T any](r *Rope[T], pattern []T) int {
+	count := 0
+	for range FindAll(r, pattern) {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,63 @@
+package rope
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	text := "abcabcab aab abcab"
+	rope := NewRope([]byte(text), testSettings) // Leaves of 2 to 4 bytes
+	matches := slices.Collect(FindAll(rope, []byte("abcab")))
+	assert(t, slices.Equal(matches, []int{0, 13}), "Wrong matches:", matches)
+	matches = slices.Collect(FindAll(rope, []byte("aab")))
+	assert(t, slices.Equal(matches, []int{9}), "Wrong matches:", matches)
+	matches = slices.Collect(FindAll(rope, []byte("ab")))
+	assert(t, len(matches) == strings.Count(text, "ab"), "Wrong matches:", matches)
+	matches = slices.Collect(FindAll(rope, []byte("x")))
+	assert(t, len(matches) == 0, "Wrong matches:", matches)
+	matches = slices.Collect(FindAll(NewRope([]byte("ab"), testSettings), []byte{}))
+	assert(t, slices.Equal(matches, []int{0, 1, 2}), "Wrong empty matches:", matches)
+
+	found := 0
+	for offset := range FindAll(rope, []byte("ab")) {
+		assert(t, text[offset:offset + 2] == "ab", "Wrong match at", offset)
+		found++
+		if found == 2 {
+			break
+		}
+	}
+	assert(t, found == 2, "Didn't stop:", found)
+}
+
+func TestFindAllWithContext(t *testing.T) {
+	text := "one\ntwo x\nthree\nfour x\nfive\nx"
+	rope := NewRope([]byte(text), testSettings)
+	matches := slices.Collect(FindAllWithContext(rope, []byte("x"), 1, 1))
+	assert(t, len(matches) == 3, "Wrong matches:", matches)
+	for _, match := range matches {
+		assert(t, text[match.Start:match.End] == "x", "Wrong match:", match)
+		assert(t, match.Line == strings.Count(text[:match.Start], "\n"), "Wrong line:", match)
+		assert(t, match.Context.Start == LineStart(rope, match.First), "Context not at a line:", match)
+	}
+	context := func(match ContextMatch) string {
+		return text[match.Context.Start:match.Context.End]
+	}
+	assert(t, context(matches[0]) == "one\ntwo x\nthree" && matches[0].First == 0, "Wrong context:", matches[0])
+	assert(t, context(matches[1]) == "three\nfour x\nfive" && matches[1].First == 2, "Wrong context:", matches[1])
+	assert(t, context(matches[2]) == "five\nx" && matches[2].First == 4, "Wrong context:", matches[2])
+
+	matches = slices.Collect(FindAllWithContext(rope, []byte("x\nthree"), 0, 0))
+	assert(t, len(matches) == 1 && context(matches[0]) == "two x\nthree", "Wrong multiline context:", matches)
+	matches = slices.Collect(FindAllWithContext(rope, []byte("four"), 5, 5))
+	assert(t, len(matches) == 1 && context(matches[0]) == text, "Context past the ends:", matches)
+
+	matches = slices.Collect(FindAllWithContext(rope, []byte("x"), -1, -1))
+	assert(t, len(matches) == 3 && context(matches[1]) == "four x", "Wrong context without lines:", matches)
+	for _, match := range matches {
+		assert(t, match.Context.Start <= match.Start && match.End <= match.Context.End, "Match outside its context:", match)
+	}
+	matches = slices.Collect(FindAllWithContext(rope, []byte("two x\n"), 0, 0))
+	assert(t, len(matches) == 1 && context(matches[0]) == "two x", "Line after the line break taken in:", matches)
+}
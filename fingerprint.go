@@ -0,0 +1,102 @@
+package rope
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The fingerprint is a pair of polynomial hashes modulo 2^61 - 1, which
+// can be combined knowing only the hashes of the sides, so it depends
+// on the content but not on the shape of the tree.
+const (
+	fingerprintPrime = 1 << 61 - 1
+	fingerprintBase1 = 0x1b873593
+	fingerprintBase2 = 0x5bd1e995
+)
+
+type fingerprintKey struct{}
+
+type fingerprint struct {
+	hash1, hash2   uint64
+	power1, power2 uint64 // The bases to the number of bytes hashed
+}
+
+// Returns a hash of the content, usable as a map key. It is cached on the
+// nodes, so after an edit only the changed path is hashed again.
+// Elements are hashed by their memory, so types containing pointers,
+// like strings, are hashed by where their data is, not its content.
+func (r *Rope[T]) Fingerprint() [16]byte {
+	return r.fingerprint().sum()
+}
+
+func (hash fingerprint) sum() [16]byte {
+	var sum [16]byte
+	binary.LittleEndian.PutUint64(sum[:8], hash.hash1)
+	binary.LittleEndian.PutUint64(sum[8:], hash.hash2)
+	return sum
+}
+
+func (r *Rope[T]) fingerprint() fingerprint {
+	return cachedSummary(r, fingerprintKey{}, func() fingerprint {
+		if r.value != nil {
+			return hashBytes(elementBytes(r.value))
+		}
+		return combineFingerprints(r.left.fingerprint(), r.right.fingerprint())
+	})
+}
+
+// Returns the fingerprint of left followed by right
+func combineFingerprints(left, right fingerprint) fingerprint {
+	return fingerprint{
+		hash1:  addMod(mulMod(left.hash1, right.power1), right.hash1),
+		hash2:  addMod(mulMod(left.hash2, right.power2), right.hash2),
+		power1: mulMod(left.power1, right.power1),
+		power2: mulMod(left.power2, right.power2),
+	}
+}
+
+// Returns the fingerprint of the range, from the ones cached on the nodes
+// inside it, hashing only the parts of the leaves at its ends
+func (r *Rope[T]) rangeFingerprint(start, end int) fingerprint {
+	if start == 0 && end == r.length {
+		return r.fingerprint()
+	}
+	if r.value != nil {
+		return hashBytes(elementBytes(r.value[start:end]))
+	}
+	leftStart, leftEnd := bound(start, end, r.left.length)
+	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
+	if rightStart == rightEnd {
+		return r.left.rangeFingerprint(leftStart, leftEnd)
+	}
+	if leftStart == leftEnd {
+		return r.right.rangeFingerprint(rightStart, rightEnd)
+	}
+	return combineFingerprints(r.left.rangeFingerprint(leftStart, leftEnd), r.right.rangeFingerprint(rightStart, rightEnd))
+}
+
+func hashBytes(data []byte) fingerprint {
+	hash := fingerprint{power1: 1, power2: 1}
+	for _, b := range data {
+		hash.hash1 = addMod(mulMod(hash.hash1, fingerprintBase1), uint64(b) + 1)
+		hash.hash2 = addMod(mulMod(hash.hash2, fingerprintBase2), uint64(b) + 1)
+		hash.power1 = mulMod(hash.power1, fingerprintBase1)
+		hash.power2 = mulMod(hash.power2, fingerprintBase2)
+	}
+	return hash
+}
+
+func mulMod(a, b uint64) uint64 {
+	high, low := bits.Mul64(a, b)
+	// As 2^64 = 8 and 2^61 = 1 (mod 2^61 - 1), the product is high * 8 + low,
+	// and low is its top 3 bits plus the rest
+	return addMod(addMod(high << 3 | low >> 61, 0), low & fingerprintPrime)
+}
+
+func addMod(a, b uint64) uint64 {
+	sum := a + b
+	if sum >= fingerprintPrime {
+		sum -= fingerprintPrime
+	}
+	return sum
+}
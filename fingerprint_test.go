@@ -0,0 +1,34 @@
+package rope
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	originalValue := make([]int, 100)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	rope := NewRope(originalValue, testSettings)
+	reshaped := NewRope(originalValue[:50], DefaultSettings).Insert(50, originalValue[50:])
+	changed := rope.Replace(40, []int{-1})
+
+	assert(t, rope.Fingerprint() == reshaped.Fingerprint(), "Fingerprint depends on the shape")
+	assert(t, rope.Fingerprint() != changed.Fingerprint(), "Fingerprint ignores the content")
+	assert(t, changed.Fingerprint() == NewRope(changed.Value(), DefaultSettings).Fingerprint(),
+		"Cached fingerprints went stale")
+	assert(t, NewRope([]int{0}, testSettings).Fingerprint() != NewRope([]int{0, 0}, testSettings).Fingerprint(),
+		"Fingerprint ignores the length")
+
+	var wg sync.WaitGroup
+	fresh := NewRope(originalValue, testSettings)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert(t, fresh.Fingerprint() == rope.Fingerprint(), "Concurrent fingerprint differs")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,22 @@
+package rope
+
+// Marks the rope and all its nodes as frozen, and returns it. Edits work
+// as usual, returning new versions, but anything that would modify the
+// rope in place, like Rebalance or unmarshaling into it, panics or fails
+// with ErrFrozen, so the rope can be handed to other goroutines without
+// synchronization.
+func (r *Rope[T]) Freeze() *Rope[T] {
+	if r.frozen { // Then, so is everything below
+		return r
+	}
+	r.frozen = true
+	if r.value == nil {
+		r.left.Freeze()
+		r.right.Freeze()
+	}
+	return r
+}
+
+func (r *Rope[T]) IsFrozen() bool {
+	return r.frozen
+}
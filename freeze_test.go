@@ -0,0 +1,24 @@
+package rope
+
+import "testing"
+
+func TestFreeze(t *testing.T) {
+	settings := *testSettings
+	settings.Rebalance = 1.5
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	for i := 0; i < 10; i++ {
+		rope = rope.Insert(0, []int{i})
+	}
+	frozen := rope.Freeze()
+	depth := frozen.Depth()
+	assert(t, frozen == rope && frozen.IsFrozen() && frozen.left.IsFrozen(), "Not frozen")
+	assertPanics(t, ErrFrozen, frozen.Rebalance)
+	assert(t, frozen.UnmarshalJSON([]byte("[]")) == ErrFrozen, "Unmarshaled into a frozen rope")
+
+	derived := frozen.Insert(frozen.Length(), []int{-1})
+	assert(t, !derived.IsFrozen(), "Derived rope frozen")
+	derived.Rebalance()
+	assert(t, derived.IsBalanced(), "Derived rope not rebalanced")
+	assert(t, frozen.Depth() == depth && !frozen.IsBalanced(), "Frozen rope modified")
+	assert(t, frozen.Validate() == nil, "Invalid rope:", frozen.Validate())
+}
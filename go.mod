@@ -1,3 +1,5 @@
 module github.com/hhhhhhhhhn/rope
 
-go 1.18
+go 1.23
+
+require golang.org/x/text v0.21.0
@@ -0,0 +1,13 @@
+package rope
+
+import (
+	"net/http"
+	"time"
+)
+
+// Serves the byte rope with http.ServeContent, which handles range
+// requests, conditional requests with the modtime, and the content type
+// from the name's extension or the content
+func ServeContent(w http.ResponseWriter, req *http.Request, name string, modtime time.Time, r *Rope[byte]) {
+	http.ServeContent(w, req, name, modtime, NewReader(r))
+}
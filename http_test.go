@@ -0,0 +1,26 @@
+package rope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServeContent(t *testing.T) {
+	rope := NewRope([]byte("<p>a rope served over http</p>"), testSettings)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ServeContent(w, req, "page.html", time.Unix(0, 0), rope)
+	})
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest("GET", "/", nil))
+	assert(t, recorder.Code == 200 && recorder.Body.String() == string(rope.Value()), "Wrong response:", recorder.Code, recorder.Body)
+	assert(t, recorder.Header().Get("Content-Type") == "text/html; charset=utf-8", "Wrong type:", recorder.Header())
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Range", "bytes=5-8")
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	assert(t, recorder.Code == 206 && recorder.Body.String() == "rope", "Wrong range:", recorder.Code, recorder.Body)
+}
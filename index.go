@@ -0,0 +1,119 @@
+package rope
+
+import (
+	"bytes"
+	"iter"
+)
+
+// Bits in the trigram filter of a node
+const trigramBits = 1024
+
+// A Bloom filter of the trigrams of a node, including the ones crossing
+// between its children, so a pattern can only be inside the node if all
+// its trigrams are set
+type trigrams [trigramBits / 64]uint64
+
+type trigramKey struct{}
+
+func trigramBit(a, b, c byte) uint32 {
+	return (uint32(a) << 16 | uint32(b) << 8 | uint32(c)) * 0x9E3779B1 >> (32 - 10)
+}
+
+func (t *trigrams) add(data []byte) {
+	for i := 0; i + 2 < len(data); i++ {
+		bit := trigramBit(data[i], data[i + 1], data[i + 2])
+		t[bit / 64] |= 1 << (bit % 64)
+	}
+}
+
+func (t *trigrams) containsAll(pattern *trigrams) bool {
+	for i := range t {
+		if t[i] & pattern[i] != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Computed the first time a node is searched, and kept like the other
+// summaries, so after an edit only the new nodes are indexed again
+func nodeTrigrams(r *Rope[byte]) *trigrams {
+	return cachedSummary(r, trigramKey{}, func() *trigrams {
+		filter := &trigrams{}
+		if r.value != nil {
+			filter.add(r.value)
+			return filter
+		}
+		left, right := nodeTrigrams(r.left), nodeTrigrams(r.right)
+		for i := range filter {
+			filter[i] = left[i] | right[i]
+		}
+		start, end := bound(r.left.length - 2, r.left.length + 2, r.length)
+		boundary := make([]byte, end - start)
+		r.copySlice(boundary, start, end)
+		filter.add(boundary)
+		return filter
+	})
+}
+
+// Like FindAll, but skipping the subtrees that can't contain the pattern,
+// by keeping a filter of the trigrams of every node searched. Repeated
+// searches over large, mostly unchanged ropes are then sub-linear, at the
+// cost of 128 bytes for each node. Patterns under 3 bytes scan everything.
+func IndexedFindAll(r *Rope[byte], pattern []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(pattern) == 0 {
+			for offset := range FindAll(r, pattern) {
+				if !yield(offset) {
+					return
+				}
+			}
+			return
+		}
+		filter := &trigrams{}
+		filter.add(pattern)
+		next := 0 // Where the last match ended, as matches don't overlap
+		indexedFind(r, pattern, filter, 0, func(offset int) bool {
+			if offset < next {
+				return true
+			}
+			next = offset + len(pattern)
+			return yield(offset)
+		})
+	}
+}
+
+// Yields the matches inside the node, in order, with the ones crossing
+// between the children found at the node itself
+func indexedFind(r *Rope[byte], pattern []byte, filter *trigrams, offset int, yield func(int) bool) bool {
+	if r.length < len(pattern) || !nodeTrigrams(r).containsAll(filter) {
+		return true
+	}
+	if r.value != nil {
+		return findIn(r.value, pattern, offset, yield)
+	}
+	if !indexedFind(r.left, pattern, filter, offset, yield) {
+		return false
+	}
+	start, end := bound(r.left.length - len(pattern) + 1, r.left.length + len(pattern) - 1, r.length)
+	boundary := make([]byte, end - start)
+	r.copySlice(boundary, start, end)
+	if !findIn(boundary, pattern, offset + start, yield) {
+		return false
+	}
+	return indexedFind(r.right, pattern, filter, offset + r.left.length, yield)
+}
+
+// Yields every match in data, including overlapping ones
+func findIn(data, pattern []byte, offset int, yield func(int) bool) bool {
+	for start := 0; ; start++ {
+		found := bytes.Index(data[start:], pattern)
+		if found < 0 {
+			return true
+		}
+		start += found
+		if !yield(offset + start) {
+			return false
+		}
+	}
+}
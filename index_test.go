@@ -0,0 +1,28 @@
+package rope
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestIndexedFindAll(t *testing.T) {
+	text := []byte{}
+	for i := 0; i < 200; i++ {
+		text = fmt.Appendf(text, "line %d of the document\n", i)
+	}
+	rope := NewRope(text, DefaultSettings)
+	for _, pattern := range []string{"line 1", "document\nline", "of", "the d", "line 199 of", "missing", "e", ""} {
+		expected := slices.Collect(FindAll(rope, []byte(pattern)))
+		found := slices.Collect(IndexedFindAll(rope, []byte(pattern)))
+		assert(t, slices.Equal(found, expected), "Wrong matches of", pattern, found, expected)
+	}
+
+	edited := rope.Insert(1000, []byte("missing"))
+	found := slices.Collect(IndexedFindAll(edited, []byte("missing")))
+	assert(t, slices.Equal(found, []int{1000}), "Edit not indexed:", found)
+	assert(t, bytes.Equal(edited.Slice(1000, 1007), []byte("missing")), "Wrong edit")
+
+	assert(t, edited.right == rope.right && edited.right.summaries != nil, "Filter of a shared node not reused")
+}
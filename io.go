@@ -0,0 +1,63 @@
+package rope
+
+import (
+	"fmt"
+	"io"
+	"net"
+)
+
+// Works like io.ReaderAt, so byte ropes can be used as one.
+// Copies the elements starting at off into p, returning io.EOF
+// if there weren't enough to fill it. Only the offset is an int64, as
+// io.ReaderAt requires: lengths are tracked as int, so ropes are limited
+// to math.MaxInt elements, 2^31 - 1 on 32-bit platforms, and edits
+// growing them past it panic with ErrTooLong.
+func (r *Rope[T]) ReadAt(p []T, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("%w: offset %d", ErrIndexOutOfRange, off)
+	}
+	if off >= int64(r.length) {
+		return 0, io.EOF
+	}
+	start := int(off)
+	end := r.length
+	if len(p) < end - start {
+		end = start + len(p)
+	}
+	r.copySlice(p, start, end)
+	if end - start < len(p) {
+		return end - start, io.EOF
+	}
+	return end - start, nil
+}
+
+// Writes the bytes in the range, leaf by leaf, without copying them,
+// reporting progress to OnProgress after each leaf
+func WriteRangeTo(w io.Writer, r *Rope[byte], start, end int) (int64, error) {
+	start, end, err := r.checkRange(start, end)
+	if err != nil {
+		return 0, err
+	}
+	var written int64
+	r.walk(start, end, func(chunk []byte) bool {
+		var n int
+		n, err = w.Write(chunk)
+		written += int64(n)
+		progress(r.settings, int(written), end - start)
+		return err == nil
+	})
+	return written, err
+}
+
+// Returns the parts of the leaves in the range, without copying them,
+// so they can be written with a single writev to a net.Conn.
+// The buffers must not be modified.
+func Buffers(r *Rope[byte], start, end int) net.Buffers {
+	start, end = r.mustRange(start, end)
+	buffers := net.Buffers{}
+	r.walk(start, end, func(chunk []byte) bool {
+		buffers = append(buffers, chunk)
+		return true
+	})
+	return buffers
+}
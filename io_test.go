@@ -0,0 +1,54 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestReadAt(t *testing.T) {
+	rope := NewRope([]byte("hello, world"), testSettings)
+	var readerAt io.ReaderAt = rope
+	buffer := make([]byte, 5)
+
+	n, err := readerAt.ReadAt(buffer, 7)
+	assert(t, n == 5 && err == nil && string(buffer) == "world", "Wrong read:", n, err, string(buffer))
+	n, err = readerAt.ReadAt(buffer, 10)
+	assert(t, n == 2 && err == io.EOF && string(buffer[:n]) == "ld", "Wrong read:", n, err, string(buffer[:n]))
+	n, err = readerAt.ReadAt(buffer, 12)
+	assert(t, n == 0 && err == io.EOF, "Wrong read:", n, err)
+	_, err = readerAt.ReadAt(buffer, -1)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+}
+
+func TestWriteRangeTo(t *testing.T) {
+	rope := NewRope([]byte("hello, world"), testSettings)
+	buffer := &bytes.Buffer{}
+	n, err := WriteRangeTo(buffer, rope, 3, 10)
+	assert(t, n == 7 && err == nil && buffer.String() == "lo, wor", "Wrong write:", n, err, buffer.String())
+	_, err = WriteRangeTo(buffer, rope, 3, 13)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+}
+
+func TestBuffers(t *testing.T) {
+	rope := NewRope([]byte("hello, world"), testSettings)
+	buffers := Buffers(rope, 3, 10)
+	assert(t, len(buffers) > 1, "Leaves not kept apart:", len(buffers))
+	leaf, leafStart := rope.leafAt(3)
+	assert(t, &buffers[0][0] == &leaf.value[3 - leafStart], "Leaf copied")
+	written := &bytes.Buffer{}
+	buffers.WriteTo(written)
+	assert(t, written.String() == "lo, wor", "Wrong write:", written.String())
+}
+
+func TestTooLong(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	rope.length = math.MaxInt - 1 // Faked, to not need the memory
+
+	err := rope.checkGrowth(2)
+	assert(t, errors.Is(err, ErrTooLong), "Overflow not detected")
+	_, err = rope.TryInsert(0, []int{0, 1})
+	assert(t, errors.Is(err, ErrTooLong), "Wrong error:", err)
+}
@@ -0,0 +1,34 @@
+package rope
+
+import "iter"
+
+// Yields the parts of the leaves in the range, in order, without copying
+// them, so they must not be modified. The range is checked when iterating,
+// which keeps Chunks small enough to be inlined, so ranging over it
+// doesn't allocate.
+func (r *Rope[T]) Chunks(start, end int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		r.ForEachRange(start, end, yield)
+	}
+}
+
+// Yields the indices and values in the range, checked when iterating
+func (r *Rope[T]) All(start, end int) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		r.forEachIndexed(start, end, yield)
+	}
+}
+
+func (r *Rope[T]) forEachIndexed(start, end int, yield func(int, T) bool) {
+	start, end = r.mustRange(start, end)
+	index := start
+	r.walk(start, end, func(chunk []T) bool {
+		for _, value := range chunk {
+			if !yield(index, value) {
+				return false
+			}
+			index++
+		}
+		return true
+	})
+}
@@ -0,0 +1,32 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChunksIterator(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	chunks := [][]int{}
+	for chunk := range rope.Chunks(2, 7) {
+		chunks = append(chunks, chunk)
+	}
+	assert(t, len(chunks) == 2 && slices.Equal(slices.Concat(chunks...), []int{2, 3, 4, 5, 6}), "Wrong chunks:", chunks)
+	for chunk := range rope.Chunks(0, 8) {
+		assert(t, len(chunk) == 4, "Stopped late:", chunk)
+		break
+	}
+}
+
+func TestAll(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	count := 0
+	for index, value := range rope.All(3, 8) {
+		assert(t, index == value, "Wrong index:", index, value)
+		count++
+		if index == 5 {
+			break
+		}
+	}
+	assert(t, count == 3, "Wrong count:", count)
+}
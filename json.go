@@ -0,0 +1,29 @@
+package rope
+
+import "encoding/json"
+
+// Marshals as the array of the values
+func (r *Rope[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Value())
+}
+
+// Unmarshals from an array of values, keeping the settings of the rope,
+// or using DefaultSettings if it has none
+func (r *Rope[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if r.frozen {
+		return ErrFrozen
+	}
+	values := []T{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	settings := r.settings
+	if settings == nil {
+		settings = DefaultSettings
+	}
+	*r = *NewRope(values, settings)
+	return nil
+}
@@ -0,0 +1,35 @@
+package rope
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type document struct {
+		Name string
+		Text *Rope[int]
+	}
+	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	original := document{"test", NewRope(originalValue, testSettings)}
+
+	data, err := json.Marshal(original)
+	assert(t, err == nil, "Unexpected error:", err)
+	assert(t, string(data) == `{"Name":"test","Text":[0,1,2,3,4,5,6,7]}`, "Wrong JSON:", string(data))
+
+	decoded := document{}
+	err = json.Unmarshal(data, &decoded)
+	assert(t, err == nil, "Unexpected error:", err)
+	assertValue(t, decoded.Text, originalValue)
+	assert(t, decoded.Text.settings == DefaultSettings, "Default settings not used")
+
+	withSettings := document{Text: NewRope([]int{}, testSettings)}
+	err = json.Unmarshal([]byte(`{"Text":[1,2,3,4,5]}`), &withSettings)
+	assert(t, err == nil, "Unexpected error:", err)
+	assertValue(t, withSettings.Text, []int{1, 2, 3, 4, 5})
+	assert(t, withSettings.Text.settings == testSettings && withSettings.Text.Validate() == nil,
+		"Settings not kept")
+
+	err = json.Unmarshal([]byte(`{"Text":["a"]}`), &decoded)
+	assert(t, err != nil, "Invalid JSON accepted")
+}
@@ -0,0 +1,118 @@
+package rope
+
+import (
+	"iter"
+	"sort"
+)
+
+type Token struct {
+	Range
+	Kind  int // Defined by the lexer
+	State int // Of the lexer after the token
+}
+
+// The tokens of a rope, sorted and covering it
+type Tokens []Token
+
+// Splits text into tokens, one at a time. Lex reads the token at the start
+// of the input, in the state the lexer was left in by the previous one, 0
+// for the first, and returns its kind and the state after it. It must read
+// at least one byte, and the token is made of the bytes read. Lexers mustn't
+// depend on anything but the state and the input, so tokens after an edit
+// can be kept once the lexer reaches their start in the same state.
+type Lexer interface {
+	Lex(input *LexInput, state int) (kind, next int)
+}
+
+// Reads the bytes of a rope for a lexer, from its chunks, without copying
+type LexInput struct {
+	next   func() ([]byte, bool)
+	stop   func()
+	chunk  []byte
+	offset int // Of the start of chunk
+	read   int // In chunk
+}
+
+func newLexInput(r *Rope[byte], start int) *LexInput {
+	next, stop := iter.Pull(r.Chunks(start, r.length))
+	return &LexInput{next: next, stop: stop, offset: start}
+}
+
+// Returns the offset of the next byte
+func (l *LexInput) Offset() int {
+	return l.offset + l.read
+}
+
+// Returns the next byte without reading it, or false at the end
+func (l *LexInput) Peek() (byte, bool) {
+	for l.read == len(l.chunk) {
+		chunk, ok := l.next()
+		if !ok {
+			return 0, false
+		}
+		l.offset += len(l.chunk)
+		l.chunk, l.read = chunk, 0
+	}
+	return l.chunk[l.read], true
+}
+
+// Reads the next byte, or returns false at the end
+func (l *LexInput) Next() (byte, bool) {
+	b, ok := l.Peek()
+	if ok {
+		l.read++
+	}
+	return b, ok
+}
+
+// Returns the tokens of r, the result of applying the edits, sorted by
+// Start as in a Patch, to the rope with the tokens prev. Only the text
+// from the end of the first token ending before the first edit is lexed,
+// until a token ends, after the last edit, where one in prev ended in
+// the same state, after which the rest of prev is kept, moved.
+func Relex(r *Rope[byte], edits []Edit[byte], lexer Lexer, prev Tokens) Tokens {
+	if len(edits) == 0 {
+		return prev
+	}
+	stable := sort.Search(len(prev), func(i int) bool { return prev[i].End >= edits[0].Start })
+	tokens := append(Tokens{}, prev[:stable]...)
+	start, state := 0, 0
+	if stable > 0 {
+		start, state = prev[stable - 1].End, prev[stable - 1].State
+	}
+	delta := 0
+	for _, edit := range edits {
+		delta += len(edit.With) - (edit.End - edit.Start)
+	}
+	editsEnd := edits[len(edits) - 1].End + delta // After the edits
+
+	input := newLexInput(r, start)
+	defer input.stop()
+	old := stable // The first token in prev ending after the lexed ones
+	for {
+		if _, ok := input.Peek(); !ok {
+			return tokens
+		}
+		var kind int
+		kind, state = lexer.Lex(input, state)
+		if input.Offset() == start { // Nothing was read
+			input.Next()
+		}
+		tokens = append(tokens, Token{Range{start, input.Offset()}, kind, state})
+		start = input.Offset()
+		if start < editsEnd {
+			continue
+		}
+		for old < len(prev) && prev[old].End + delta < start {
+			old++
+		}
+		if old < len(prev) && prev[old].End + delta == start && prev[old].State == state {
+			for _, token := range prev[old + 1:] {
+				token.Start += delta
+				token.End += delta
+				tokens = append(tokens, token)
+			}
+			return tokens
+		}
+	}
+}
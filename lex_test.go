@@ -0,0 +1,89 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+const (
+	tokenWord = iota
+	tokenSpace
+	tokenComment
+	tokenOther
+)
+
+// Lexes words, spaces, and /* */ comments, one token per line of them,
+// with the state 1 after a line ending inside one
+type testLexer struct {
+	calls int
+}
+
+func (l *testLexer) Lex(input *LexInput, state int) (kind, next int) {
+	l.calls++
+	if state == 1 {
+		return lexComment(input)
+	}
+	b, _ := input.Next()
+	switch {
+	case b == '/' && peekIs(input, '*'):
+		input.Next()
+		return lexComment(input)
+	case b >= 'a' && b <= 'z':
+		for c, ok := input.Peek(); ok && c >= 'a' && c <= 'z'; c, ok = input.Peek() {
+			input.Next()
+		}
+		return tokenWord, 0
+	case b == ' ':
+		for peekIs(input, ' ') {
+			input.Next()
+		}
+		return tokenSpace, 0
+	}
+	return tokenOther, 0
+}
+
+func lexComment(input *LexInput) (kind, next int) {
+	var previous byte
+	for {
+		b, ok := input.Next()
+		switch {
+		case !ok || b == '\n':
+			return tokenComment, 1
+		case previous == '*' && b == '/':
+			return tokenComment, 0
+		}
+		previous = b
+	}
+}
+
+func peekIs(input *LexInput, b byte) bool {
+	c, ok := input.Peek()
+	return ok && c == b
+}
+
+// Lexes the whole rope
+func lexAll(r *Rope[byte], lexer Lexer) Tokens {
+	return Relex(r, []Edit[byte]{{0, 0, nil}}, lexer, nil)
+}
+
+func TestRelex(t *testing.T) {
+	text := []byte("int a /* one\ntwo */ b\nc d e f\ng h i j")
+	rope := NewRope(text, testSettings)
+	lexer := &testLexer{}
+	tokens := lexAll(rope, lexer)
+	assert(t, tokens[4] == Token{Range{6, 13}, tokenComment, 1} && tokens[5] == Token{Range{13, 19}, tokenComment, 0},
+		"Wrong comment tokens", tokens)
+	assert(t, tokens[len(tokens) - 1].End == len(text), "Tokens don't cover the rope")
+
+	edits := []Edit[byte]{{21, 21, []byte("x")}, {26, 27, []byte("ee")}} // "bx" and "ee"
+	edited := Patch[byte](edits).Apply(rope)
+	lexer.calls = 0
+	relexed := Relex(edited, edits, lexer, tokens)
+	assert(t, slices.Equal(relexed, lexAll(edited, &testLexer{})), "Wrong tokens after the edits", relexed)
+	assert(t, lexer.calls == 7, "Tokens far from the edits lexed again", lexer.calls)
+
+	edits = []Edit[byte]{{12, 13, nil}} // Joins the lines of the comment
+	edited = Patch[byte](edits).Apply(rope)
+	relexed = Relex(edited, edits, lexer, tokens)
+	assert(t, slices.Equal(relexed, lexAll(edited, &testLexer{})), "Wrong tokens after changing the state", relexed)
+}
@@ -0,0 +1,88 @@
+package rope
+
+type LineEnding int
+
+const (
+	LF   LineEnding = iota // \n, as in Unix
+	CRLF                   // \r\n, as in Windows
+	CR                     // \r, as in old Mac OS
+)
+
+func (e LineEnding) String() string {
+	return [...]string{"LF", "CRLF", "CR"}[e]
+}
+
+func (e LineEnding) Bytes() []byte {
+	switch e {
+	case CRLF:
+		return []byte("\r\n")
+	case CR:
+		return []byte("\r")
+	}
+	return []byte("\n")
+}
+
+// Returns the line ending used the most in the text, LF if it has no
+// line breaks, and whether it uses more than one
+func DetectLineEndings(r *Rope[byte]) (ending LineEnding, mixed bool) {
+	counts := [3]int{}
+	scanLineEndings(r, func(offset int, ending LineEnding) {
+		counts[ending]++
+	})
+	for _, candidate := range []LineEnding{CRLF, CR} {
+		if counts[candidate] > counts[ending] {
+			ending = candidate
+		}
+	}
+	used := 0
+	for _, count := range counts {
+		if count > 0 {
+			used++
+		}
+	}
+	return ending, used > 1
+}
+
+// Returns the rope with every line break as target, in a single pass
+// over the leaves, sharing the ones far from the line breaks changed
+func NormalizeLineEndings(r *Rope[byte], target LineEnding) *Rope[byte] {
+	patch := Patch[byte]{}
+	scanLineEndings(r, func(offset int, ending LineEnding) {
+		if ending != target {
+			patch = patch.join(r, Edit[byte]{offset, offset + len(ending.Bytes()), target.Bytes()})
+		}
+	})
+	return patch.Apply(r)
+}
+
+// Calls visit with the line breaks in order, including the \r\n split
+// between leaves
+func scanLineEndings(r *Rope[byte], visit func(offset int, ending LineEnding)) {
+	offset := 0
+	carriageReturn := -1 // Where the last \r is, if it was the last byte
+	r.walk(0, r.length, func(chunk []byte) bool {
+		for _, b := range chunk {
+			if carriageReturn >= 0 {
+				if b == '\n' {
+					visit(carriageReturn, CRLF)
+					carriageReturn = -1
+					offset++
+					continue
+				}
+				visit(carriageReturn, CR)
+				carriageReturn = -1
+			}
+			switch b {
+			case '\r':
+				carriageReturn = offset
+			case '\n':
+				visit(offset, LF)
+			}
+			offset++
+		}
+		return true
+	})
+	if carriageReturn >= 0 {
+		visit(carriageReturn, CR)
+	}
+}
@@ -0,0 +1,36 @@
+package rope
+
+import "testing"
+
+func TestDetectLineEndings(t *testing.T) {
+	cases := []struct {
+		text   string
+		ending LineEnding
+		mixed  bool
+	}{
+		{"no breaks", LF, false},
+		{"a\nb\n", LF, false},
+		{"a\r\nb\r\nc\n", CRLF, true},
+		{"a\rb\r", CR, false},
+		{"abc\r", CR, false},
+	}
+	for _, c := range cases {
+		ending, mixed := DetectLineEndings(NewRope([]byte(c.text), testSettings))
+		assert(t, ending == c.ending && mixed == c.mixed, "Wrong line endings for", c.text, ":", ending, mixed)
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	// With leaves of 4, the first \r\n is split between leaves
+	rope := NewRope([]byte("abc\r\ndef\rg\nh\r"), testSettings)
+	assertValue(t, NormalizeLineEndings(rope, LF), []byte("abc\ndef\ng\nh\n"))
+	assertValue(t, NormalizeLineEndings(rope, CRLF), []byte("abc\r\ndef\r\ng\r\nh\r\n"))
+	assertValue(t, NormalizeLineEndings(rope, CR), []byte("abc\rdef\rg\rh\r"))
+
+	text := make([]byte, 4000)
+	text[3000] = '\r'
+	long := NewRope(text, DefaultSettings)
+	normalized := NormalizeLineEndings(long, LF)
+	assert(t, normalized.At(3000) == '\n' && normalized.SharedWith(long) > 0.5, "Untouched leaves not shared")
+	assert(t, NormalizeLineEndings(normalized, LF) == normalized, "Unchanged rope not shared")
+}
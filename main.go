@@ -1,9 +1,46 @@
 package rope
 
+import (
+	"sort"
+	"unsafe"
+)
+
 type Settings struct {
-	SplitLength int     // Maximum length before to split a rope
-	JoinLength  int     // Minimum length to join a rope
-	Rebalance   float32 // Ratio needed to rebalance a rope
+	SplitLength int      // Maximum length before to split a rope
+	JoinLength  int      // Minimum length to join a rope
+	Rebalance   float32  // Ratio needed to rebalance a rope
+	Arena       *Arena   // Optional allocator for the nodes
+	Pool        *Pool    // Optional recycler of the nodes and leaves of released ropes
+	GapBuffer   bool     // Whether to keep spare capacity at insertion points
+	LazyRemove  bool     // Whether to remove by slicing leaves instead of copying them, until Compact
+	Log         *OpLog   // Optional log of the operations, for debugging
+	Metrics     *Metrics // Optional counters, for observability
+
+	OutOfRange RangePolicy // What to do with indices out of range, panic if unset
+	FromEnd    bool        // Whether negative indices count from the end
+
+	AutoRebalance bool // Whether to rebalance after edits unbalancing the root
+
+	// Optional func(leaf []T) int, for the T of the ropes using the settings,
+	// choosing where leaves are split instead of the midpoint
+	SplitAt any
+
+	// Optional Balancer[T], for the T of the ropes using the settings,
+	// used by Rebalance instead of rebuilding with the Rebalance ratio
+	Balancer any
+
+	// Optional func(a, b T) bool, for the T of the ropes using the settings,
+	// deciding if elements are equal when searching, comparing or diffing,
+	// instead of == for comparable types and reflect.DeepEqual for the rest
+	Eq any
+
+	MaxDepth int // If set, edits leaving the rope deeper rebuild the deepest subtrees
+
+	// Optional callbacks, to collect metrics, check the thresholds or show progress
+	OnSplit     func(left, right int) // A leaf was split, with the lengths of the sides
+	OnJoin      func(length int)      // A split node was joined into a leaf
+	OnRebalance func(length int)      // A node was rebuilt by Rebalance
+	OnProgress  func(done, total int) // Elements done by a bulk operation, total -1 if unknown
 }
 
 var DefaultSettings = &Settings {
@@ -12,100 +49,270 @@ var DefaultSettings = &Settings {
 	Rebalance:   1.5,
 }
 
+// Copy of DefaultSettings with the lengths scaled down by the size of T,
+// so leaves stay around the same size in bytes as leaves of bytes.
+func DefaultSettingsFor[T any]() *Settings {
+	var element T
+	settings := *DefaultSettings
+	size := int(unsafe.Sizeof(element))
+	if size > 1 {
+		settings.SplitLength = DefaultSettings.SplitLength / size
+		settings.JoinLength = DefaultSettings.JoinLength / size
+		if settings.SplitLength < 2 { // Smaller would split single elements
+			settings.SplitLength = 2
+		}
+		if settings.JoinLength < 1 {
+			settings.JoinLength = 1
+		}
+	}
+	return &settings
+}
+
 type Rope[T any] struct {
 	value    []T
 	length   int
 	left     *Rope[T]
 	right    *Rope[T]
 	settings *Settings
+	claim    *claim // Only set on leaves with spare capacity to append into
+	piece    bool   // Leaf longer than SplitLength, split only when edited
+	summaries unsafe.Pointer // *summary, cached by cachedSummary
+	frozen   bool   // Set by Freeze, so it is never modified in place
+	owned    uint32 // 1 on leaves with an array allocated for them alone, see Release
+	height   int    // Depth() - 1, so leaves have 0
 }
 
+// Values longer than this many SplitLengths are built bottom-up by buildRope
+const bulkLeaves = 4
+
 func NewRope[T any](value []T, settings *Settings) *Rope[T] {
-	rope := &Rope[T]{value: value, length: len(value), settings: settings}
+	if settings == nil {
+		panic(ErrNilSettings)
+	}
+	if len(value) > bulkLeaves * settings.SplitLength {
+		return buildRope(value, settings)
+	}
+	if value == nil { // A nil value would mark the leaf as split
+		value = []T{}
+	}
+	rope := newNode[T](settings)
+	rope.value = value
+	rope.length = len(value)
 	rope.adjust()
 	return rope
 }
 
+// Creates a rope of length 0, which supports every operation,
+// although the only valid index to insert at is 0.
+func Empty[T any](settings *Settings) *Rope[T] {
+	return NewRope([]T{}, settings)
+}
+
 func (r *Rope[T]) adjust() {
+	if r.value == nil && (r.left.length == 0 || r.right.length == 0) { // A side is empty
+		// Takes the place of the other side, which is already adjusted
+		kept := r.left
+		if r.left.length == 0 {
+			kept = r.right
+		}
+		kept.disown() // Its array is now viewed by both
+		r.value, r.left, r.right, r.piece, r.claim, r.height = kept.value, kept.left, kept.right, kept.piece, kept.claim, kept.height
+		return
+	}
 	if r.value != nil && r.length > r.settings.SplitLength { // It is not yet split but too long
-		r.left  = NewRope(r.value[:r.length / 2], r.settings)
-		r.right = NewRope(r.value[r.length / 2:], r.settings)
+		// Capacities are clamped, so the halves can't write over each other
+		split := r.splitPoint()
+		r.left  = NewRope(r.value[:split:split], r.settings)
+		r.right = NewRope(r.value[split:r.length:r.length], r.settings)
 		r.value = nil // Mark as split
+		r.updateHeight()
+		if r.settings.OnSplit != nil {
+			r.settings.OnSplit(r.left.length, r.right.length)
+		}
 		return
 	}
 	if r.value == nil && r.length < r.settings.JoinLength { // It is split but too short
 		r.value = make([]T, r.length)
+		r.countCopied(r.length)
 		r.left.Copy(r.value)
-		r.right.Copy(r.value[:r.left.length])
+		r.right.Copy(r.value[r.left.length:])
 		r.left = nil
 		r.right = nil
+		r.owned = 1
+		if r.settings.OnJoin != nil {
+			r.settings.OnJoin(r.length)
+		}
+	}
+	r.updateHeight()
+}
+
+// Must be called after linking the sides of a node
+func (r *Rope[T]) updateHeight() {
+	if r.value != nil {
+		r.height = 0
+	} else {
+		r.height = 1 + max(r.left.height, r.right.height)
+	}
+}
+
+// Where to split the leaf, from SplitAt if set
+func (r *Rope[T]) splitPoint() int {
+	return splitIndex(r.value[:r.length:r.length], r.settings)
+}
+
+// Where to split the value, from SplitAt if set, kept inside the value so
+// both sides are shorter. Panics if SplitAt is for another element type.
+func splitIndex[T any](value []T, settings *Settings) int {
+	if settings.SplitAt == nil {
+		return len(value) / 2
 	}
+	split := settings.SplitAt.(func([]T) int)(value)
+	if split < 1 {
+		return 1
+	}
+	if split > len(value) - 1 {
+		return len(value) - 1
+	}
+	return split
 }
 
 func (r *Rope[T]) Remove(start, end int) *Rope[T] {
+	start, end = r.mustRange(start, end)
+	changed := r.remove(start, end).limitDepth(0)
+	changed = changed.autoRebalance()
+	changed.logOperation("Remove", start, end)
+	return changed
+}
+
+func (r *Rope[T]) remove(start, end int) *Rope[T] {
 	if start == end {
 		return r
 	}
+	if start == 0 && end == r.length { // Whole leaves are dropped, not copied
+		return NewRope([]T{}, r.settings)
+	}
+	if r.value != nil && r.settings.LazyRemove {
+		return r.removeLazily(start, end)
+	}
+	if r.piece {
+		return r.expand().remove(start, end)
+	}
 	if r.value != nil { // If rope isn't split
 		// A copy is needed, as append doesn't guarantee immutability
-		newValue := make([]T, r.length - (end - start))
+		newValue := newLeafValue[T](r.settings, r.length - (end - start))
+		r.countCopied(len(newValue))
 		copy(newValue, r.value[:start])
 		copy(newValue[start:], r.value[end:])
-		changed := NewRope(newValue, r.settings)
+		changed := ownedLeaf(newValue, r.settings)
 		return changed
 	}
 	// Rope is split
-	changed := &Rope[T]{settings: r.settings}
 	leftStart, leftEnd := bound(start, end, r.left.length)
-	changed.left = r.left.Remove(leftStart, leftEnd)
-
 	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
-	changed.right = r.right.Remove(rightStart, rightEnd)
+	if leftStart == 0 && leftEnd == r.left.length { // Only the right side is left
+		return r.right.remove(rightStart, rightEnd)
+	}
+	if rightStart == 0 && rightEnd == r.right.length {
+		return r.left.remove(leftStart, leftEnd)
+	}
+	changed := newNode[T](r.settings)
+	changed.left = r.left.remove(leftStart, leftEnd)
+	changed.right = r.right.remove(rightStart, rightEnd)
 
 	changed.length = changed.left.length + changed.right.length
 	changed.adjust()
 	return changed
 }
 
+// Inserts before the element at index, so inserting at Length() appends
 func (r *Rope[T]) Insert(index int, insertion []T) *Rope[T] {
+	index = r.mustIndex(index, r.length)
+	must(r.checkGrowth(len(insertion)))
+	changed := r.insert(index, insertion).limitDepth(0)
+	changed = changed.autoRebalance()
+	changed.logOperation("Insert", index, index + len(insertion))
+	return changed
+}
+
+func (r *Rope[T]) insert(index int, insertion []T) *Rope[T] {
+	if len(insertion) == 0 {
+		return r
+	}
+	if r.piece {
+		return r.expand().insert(index, insertion)
+	}
+	if r.value != nil && len(insertion) > bulkLeaves * r.settings.SplitLength {
+		return r.insertSubtree(index, insertion)
+	}
 	if r.value != nil { // If rope isn't split
+		if r.settings.GapBuffer {
+			if index == r.length {
+				return r.appendLeaf(insertion)
+			}
+			if index > 0 && r.length >= r.settings.JoinLength {
+				return r.openGap(index, insertion)
+			}
+		}
+		if (index == 0 || index == r.length) && r.length + len(insertion) > r.settings.SplitLength {
+			return r.insertBeside(index, insertion)
+		}
 		// A copy is needed, as append doesn't guarantee immutability
-		newValue := make([]T, r.length + len(insertion))
+		newValue := newLeafValue[T](r.settings, r.length + len(insertion))
+		r.countCopied(len(newValue))
 		copy(newValue, r.value[:index])
 		copy(newValue[index:], insertion)
 		copy(newValue[index + len(insertion):], r.value[index:])
-		changed := NewRope(newValue, r.settings) // Takes care of adjusting
+		changed := ownedLeaf(newValue, r.settings) // Takes care of adjusting
 		return changed
 	}
 	// Rope is split
-	changed := &Rope[T]{
-		settings: r.settings,
-		length: r.length + len(insertion),
-		left: r.left,
-		right: r.right,
+	if index == r.left.length && !r.settings.GapBuffer {
+		if bridged := r.insertBridging(insertion); bridged != nil {
+			return bridged
+		}
 	}
+	changed := newNode[T](r.settings)
+	changed.length = r.length + len(insertion)
+	changed.left = r.left
+	changed.right = r.right
 
-	if index < r.left.length {
-		changed.left = r.left.Insert(index, insertion)
+	// With a gap buffer, the end of the left side is where the spare capacity is
+	if index < r.left.length || (r.settings.GapBuffer && index == r.left.length) {
+		changed.left = r.left.insert(index, insertion)
 	} else {
-		changed.right = r.right.Insert(index - r.left.length, insertion)
+		changed.right = r.right.insert(index - r.left.length, insertion)
 	}
+	changed.updateHeight()
 	return changed
 }
 
 func (r *Rope[T]) Replace(index int, replacement[]T) *Rope[T] {
+	index, replacement, err := r.checkReplacement(index, replacement)
+	must(err)
+	changed := r.replace(index, replacement).limitDepth(0)
+	changed = changed.autoRebalance()
+	changed.logOperation("Replace", index, index + len(replacement))
+	return changed
+}
+
+func (r *Rope[T]) replace(index int, replacement[]T) *Rope[T] {
 	if len(replacement) == 0 {
 		return r
 	}
+	if r.piece {
+		return r.expand().replace(index, replacement)
+	}
 	if r.value != nil { // Rope isn't split
-		newValue := make([]T, r.length)
+		newValue := newLeafValue[T](r.settings, r.length)
+		r.countCopied(len(newValue))
 		copy(newValue, r.value)
 		copy(newValue[index:], replacement)
-		changed := NewRope(newValue, r.settings) // Takes care of adjusting
+		changed := ownedLeaf(newValue, r.settings) // Takes care of adjusting
 		return changed
 	}
 	// Rope is split
-	changed := &Rope[T]{settings: r.settings, length: r.length}
+	changed := newNode[T](r.settings)
+	changed.length = r.length
 
 	leftStart, leftEnd := bound(index, index + len(replacement), r.left.length)
 	leftSlice := replacement[:leftEnd - leftStart]
@@ -116,12 +323,136 @@ func (r *Rope[T]) Replace(index int, replacement[]T) *Rope[T] {
 	)
 	rightSlice := replacement[len(leftSlice):len(leftSlice) + rightEnd - rightStart]
 
-	changed.left = r.left.Replace(leftStart, leftSlice)
-	changed.right = r.right.Replace(rightStart, rightSlice)
+	changed.left = r.left.replace(leftStart, leftSlice)
+	changed.right = r.right.replace(rightStart, rightSlice)
 	changed.adjust()
 	return changed
 }
 
+// Replaces the ranges, sorted and not overlapping as in a Patch, with
+// their values, in one pass over the tree, so each leaf with edits is
+// copied once and the subtrees without them are shared
+func (r *Rope[T]) ReplaceRanges(replacements []Edit[T]) *Rope[T] {
+	must(Patch[T](replacements).Validate(r.length))
+	if len(replacements) == 0 {
+		return r
+	}
+	growth := 0
+	for _, edit := range replacements {
+		growth += len(edit.With) - (edit.End - edit.Start)
+	}
+	must(r.checkGrowth(growth))
+	changed := r.replaceRanges(replacements).limitDepth(0)
+	changed = changed.autoRebalance()
+	last := replacements[len(replacements) - 1]
+	changed.logOperation("ReplaceRanges", replacements[0].Start, last.End + growth)
+	return changed
+}
+
+func (r *Rope[T]) replaceRanges(edits []Edit[T]) *Rope[T] {
+	if len(edits) == 0 {
+		return r
+	}
+	if r.piece {
+		return r.expand().replaceRanges(edits)
+	}
+	if r.value != nil { // Rope isn't split
+		length := r.length
+		for _, edit := range edits {
+			length += len(edit.With) - (edit.End - edit.Start)
+		}
+		newValue := newLeafValue[T](r.settings, length)[:0]
+		r.countCopied(length)
+		position := 0
+		for _, edit := range edits {
+			newValue = append(append(newValue, r.value[position:edit.Start]...), edit.With...)
+			position = edit.End
+		}
+		newValue = append(newValue, r.value[position:r.length]...)
+		return ownedLeaf(newValue, r.settings) // Takes care of adjusting
+	}
+	// Rope is split, insertions between the sides go to the left one
+	middle := r.left.length
+	split := 0
+	for split < len(edits) && edits[split].End <= middle {
+		split++
+	}
+	leftEdits, rightEdits := edits[:split:split], []Edit[T]{}
+	if split < len(edits) && edits[split].Start < middle { // Across both sides
+		straddling := edits[split]
+		leftEdits = append(leftEdits, Edit[T]{straddling.Start, middle, straddling.With})
+		rightEdits = append(rightEdits, Edit[T]{0, straddling.End - middle, nil})
+		split++
+	}
+	for _, edit := range edits[split:] {
+		rightEdits = append(rightEdits, Edit[T]{edit.Start - middle, edit.End - middle, edit.With})
+	}
+	left, right := r.left.replaceRanges(leftEdits), r.right.replaceRanges(rightEdits)
+	changed := newNode[T](r.settings)
+	changed.left, changed.right = left, right
+	changed.length = left.length + right.length
+	changed.adjust()
+	return changed
+}
+
+// Inserts at the start or end of a leaf that would be split anyway,
+// by keeping it as it is, next to a new leaf
+func (r *Rope[T]) insertBeside(index int, insertion []T) *Rope[T] {
+	inserted := make([]T, len(insertion))
+	r.countCopied(len(inserted))
+	copy(inserted, insertion)
+	if index == 0 {
+		return link(NewRope(inserted, r.settings), r)
+	}
+	return link(r, NewRope(inserted, r.settings))
+}
+
+// Inserts between the sides of a split node, merging the leaves on both
+// sides of the index with the insertion if both are shorter than
+// JoinLength and they fit in a leaf, as heavy removing leaves many short
+// leaves which would otherwise stay until a Rebalance. Returns nil if
+// they don't.
+func (r *Rope[T]) insertBridging(insertion []T) *Rope[T] {
+	last, lastStart := r.left.leafAt(r.left.length - 1)
+	first, _ := r.right.leafAt(0)
+	length := last.length + len(insertion) + first.length
+	if last.piece || first.piece || length > r.settings.SplitLength ||
+		last.length >= r.settings.JoinLength || first.length >= r.settings.JoinLength {
+		return nil
+	}
+	merged := newLeafValue[T](r.settings, length)
+	r.countCopied(length)
+	copy(merged, last.value)
+	copy(merged[last.length:], insertion)
+	copy(merged[last.length + len(insertion):], first.value)
+
+	var left, right *Rope[T]
+	if lastStart > 0 {
+		left = r.left.remove(lastStart, r.left.length)
+	}
+	if first.length < r.right.length {
+		right = r.right.remove(0, first.length)
+	}
+	return link(link(left, ownedLeaf(merged, r.settings)), right)
+}
+
+// Inserts a long insertion into a leaf as a subtree of its own, between
+// the parts of the leaf, which keep referencing its backing array
+func (r *Rope[T]) insertSubtree(index int, insertion []T) *Rope[T] {
+	inserted := make([]T, len(insertion))
+	r.countCopied(len(inserted))
+	copy(inserted, insertion)
+	changed := buildRope(inserted, r.settings)
+	r.disown()
+	if index > 0 {
+		changed = link(NewRope(r.value[:index:index], r.settings), changed)
+	}
+	if index < r.length {
+		changed = link(changed, NewRope(r.value[index:r.length:r.length], r.settings))
+	}
+	return changed
+}
+
 // Bind the start and end indexes inside a length,
 // preventing OOB.
 func bound(start, end, length int) (newStart, newEnd int) {
@@ -147,7 +478,16 @@ func (r *Rope[T]) Copy(dst []T) {
 	}
 }
 
-func (r *Rope[T]) CopySlice(dst []T, start, end int) {
+// Copies the range into dst, or as much of it as fits, like copy,
+// and returns how many elements were copied
+func (r *Rope[T]) CopySlice(dst []T, start, end int) int {
+	start, end = r.mustRange(start, end)
+	end = min(end, start + len(dst))
+	r.copySlice(dst, start, end)
+	return end - start
+}
+
+func (r *Rope[T]) copySlice(dst []T, start, end int) {
 	if start == end {
 		return
 	}
@@ -157,10 +497,35 @@ func (r *Rope[T]) CopySlice(dst []T, start, end int) {
 	}
 	// Is split
 	leftStart, leftEnd := bound(start, end, r.left.length)
-	r.left.CopySlice(dst, leftStart, leftEnd)
+	r.left.copySlice(dst, leftStart, leftEnd)
+
+	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
+	r.right.copySlice(dst[leftEnd - leftStart:], rightStart, rightEnd)
+}
+
+// Calls f with the parts of the leaves in the range, in order, without
+// copying them, so they must not be modified. Stops if f returns false.
+func (r *Rope[T]) ForEachRange(start, end int, f func(chunk []T) bool) {
+	start, end = r.mustRange(start, end)
+	r.walk(start, end, f)
+}
 
+// Calls visit with the parts of the leaves in the range, in order,
+// stopping if it returns false. Returns whether it wasn't stopped.
+func (r *Rope[T]) walk(start, end int, visit func(chunk []T) bool) bool {
+	if start == end {
+		return true
+	}
+	if r.value != nil { // Isn't split
+		return visit(r.value[start:end:end])
+	}
+	// Is split
+	leftStart, leftEnd := bound(start, end, r.left.length)
+	if !r.left.walk(leftStart, leftEnd, visit) {
+		return false
+	}
 	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
-	r.right.CopySlice(dst[leftEnd - leftStart:], rightStart, rightEnd)
+	return r.right.walk(rightStart, rightEnd, visit)
 }
 
 func (r *Rope[T]) Value() []T {
@@ -170,26 +535,142 @@ func (r *Rope[T]) Value() []T {
 }
 
 func (r *Rope[T]) Slice(start, end int) []T {
+	start, end = r.mustRange(start, end)
 	value := make([]T, end - start)
 	r.CopySlice(value, start, end)
 	return value
 }
 
+// Like Value, but appends to dst, so buffers can be reused
+func (r *Rope[T]) AppendValue(dst []T) []T {
+	return r.AppendSlice(dst, 0, r.length)
+}
+
+// Like Slice, but appends to dst, so buffers can be reused
+func (r *Rope[T]) AppendSlice(dst []T, start, end int) []T {
+	start, end = r.mustRange(start, end)
+	length := len(dst)
+	dst = append(dst, make([]T, end - start)...) // Doesn't allocate if there is capacity
+	r.CopySlice(dst[length:], start, end)
+	return dst
+}
+
+func (r *Rope[T]) At(index int) T {
+	index = r.mustIndex(index, r.length - 1)
+	leaf, leafStart := r.leafAt(index)
+	return leaf.value[index - leafStart]
+}
+
+// Returns the leaf containing the index, and where it starts
+func (r *Rope[T]) leafAt(index int) (leaf *Rope[T], leafStart int) {
+	for r.value == nil {
+		if index < leafStart + r.left.length {
+			r = r.left
+		} else {
+			leafStart += r.left.length
+			r = r.right
+		}
+	}
+	return r, leafStart
+}
+
+// Gets the values at many indices in a single traversal.
+// The values are returned in the same order as the indices.
+func (r *Rope[T]) GetMany(indices []int) []T {
+	checked := make([]int, len(indices)) // Resolved first, as FromEnd changes their order
+	for i, index := range indices {
+		checked[i] = r.mustIndex(index, r.length - 1)
+	}
+	indices = checked
+	order := make([]int, len(indices)) // Positions in indices, sorted by index
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return indices[order[a]] < indices[order[b]]
+	})
+	values := make([]T, len(indices))
+	r.getMany(indices, order, 0, values)
+	return values
+}
+
+func (r *Rope[T]) getMany(indices, order []int, offset int, values []T) {
+	if r.value != nil {
+		for _, i := range order {
+			values[i] = r.value[indices[i] - offset]
+		}
+		return
+	}
+	split := sort.Search(len(order), func(i int) bool {
+		return indices[order[i]] >= offset + r.left.length
+	})
+	if split > 0 {
+		r.left.getMany(indices, order[:split], offset, values)
+	}
+	if split < len(order) {
+		r.right.getMany(indices, order[split:], offset + r.left.length, values)
+	}
+}
+
 func (r *Rope[T]) Length() int {
 	return r.length
 }
 
-// NOTE: This is a very slow way to do things
+func (r *Rope[T]) IsEmpty() bool {
+	return r.length == 0
+}
+
+// Returns the rope rebalanced, sharing the subtrees which already are.
+// Nothing is modified, so r, and the versions sharing nodes with it,
+// stay valid, even if frozen.
+func (r *Rope[T]) Rebalanced() *Rope[T] {
+	changed := r.rebalanced(0, r.length)
+	changed.logOperation("Rebalance", 0, changed.length)
+	return changed
+}
+
+// Rebalances the rope in place. Only the root is overwritten, the nodes
+// below are replaced, but the root must not be shared: not a side of
+// another version, as made by Concat, SubRope, or edits returning one of
+// the subtrees of the version they were applied to, as it would no longer
+// match the depth cached there. Panics with ErrFrozen if the rope is frozen.
+//
+// Deprecated: use Rebalanced, which returns a new version instead.
 func (r *Rope[T]) Rebalance() {
+	if r.frozen {
+		panic(ErrFrozen)
+	}
+	*r = *r.rebalanced(0, r.length)
+	r.logOperation("Rebalance", 0, r.length)
+}
+
+// Returns r if it is already balanced. The offset and total are
+// only used for reporting progress.
+func (r *Rope[T]) rebalanced(offset, total int) *Rope[T] {
 	if r.value != nil {
-		return
+		progress(r.settings, offset + r.length, total)
+		return r
 	}
-	if float32(r.left.length) / float32(r.right.length) > r.settings.Rebalance ||
-	   float32(r.right.length) / float32(r.left.length) > r.settings.Rebalance {
-		   rebalancedRope := NewRope(r.Value(), r.settings)
-		   *r = *rebalancedRope
-	} else {
-		r.left.Rebalance()
-		r.right.Rebalance()
+	if balancer := r.balancer(); balancer.ShouldRebalance(r.info()) {
+		   rebalancedRope := balancer.Rebalance(r)
+		   if r.settings.Metrics != nil {
+			   r.settings.Metrics.rebalances.Add(1)
+		   }
+		   if r.settings.OnRebalance != nil {
+			   r.settings.OnRebalance(r.length)
+		   }
+		   progress(r.settings, offset + r.length, total)
+		   return rebalancedRope
+	}
+	left := r.left.rebalanced(offset, total)
+	right := r.right.rebalanced(offset + r.left.length, total)
+	if left == r.left && right == r.right {
+		return r
 	}
+	changed := newNode[T](r.settings)
+	changed.length = r.length
+	changed.left = left
+	changed.right = right
+	changed.updateHeight()
+	return changed
 }
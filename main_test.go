@@ -4,6 +4,7 @@ import (
 	"testing"
 	"math"
 	"fmt"
+	"math/rand"
 )
 
 func assertSameValue[T comparable](t *testing.T, a, b *Rope[T]) {
@@ -70,6 +71,39 @@ func TestInsert(t *testing.T) {
 	})
 }
 
+func TestInsertBetweenLeaves(t *testing.T) {
+	settings := *testSettings
+	settings.Metrics = NewMetrics()
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	insertion := []int{-1, -2}
+
+	changed := rope.Insert(4, insertion).Insert(0, insertion).Append(insertion)
+	insertion[0] = 100
+	assertValue(t, changed, []int{-1, -2, 0, 1, 2, 3, -1, -2, 4, 5, 6, 7, -1, -2})
+	assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate())
+	copied := settings.Metrics.Snapshot().BytesCopied
+	assert(t, copied <= 3 * 2 * 8, "Existing leaves were copied:", copied)
+	assert(t, rope.Insert(3, nil) == rope, "Empty insertion changed the rope")
+}
+
+func TestInsertBridging(t *testing.T) {
+	rope := link(NewRope([]int{0}, testSettings), NewRope([]int{1}, testSettings))
+	changed := rope.Insert(1, []int{-1})
+	assertValue(t, changed, []int{0, -1, 1})
+	assert(t, changed.value != nil, "Short leaves not merged:", changed.DebugString(4))
+
+	rope = link(NewRope([]int{0}, testSettings), NewRope([]int{1, 2}, testSettings))
+	changed = rope.Insert(1, []int{-1})
+	assertValue(t, changed, []int{0, -1, 1, 2})
+	assert(t, changed.value == nil && changed.left == rope.left, "Merged with a leaf that is not short:", changed.DebugString(4))
+
+	rope = NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, testSettings).Remove(5, 11)
+	changed = rope.Insert(5, []int{-1})
+	assertValue(t, changed, []int{0, 1, 2, 3, 4, -1, 11, 12, 13, 14, 15})
+	assert(t, changed.Stats().Leaves < rope.Stats().Leaves, "Short leaves not merged:", changed.DebugString(4))
+	assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate())
+}
+
 func TestReplace(t *testing.T) {
 	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
 	rope := NewRope(originalValue, testSettings)
@@ -81,6 +115,44 @@ func TestReplace(t *testing.T) {
 	})
 }
 
+func TestReplaceRanges(t *testing.T) {
+	value := make([]int, 100)
+	for i := range value {
+		value[i] = i
+	}
+	rope := NewRope(value, testSettings)
+	random := rand.New(rand.NewSource(3))
+	next := 1000
+	for i := 0; i < 200; i++ {
+		patch := randomPatch(random, rope.Length(), &next)
+		changed := rope.ReplaceRanges(patch)
+		assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate(), patch)
+		assertSameValue(t, changed, patch.Apply(rope))
+	}
+	assertValue(t, rope, value)
+
+	changed := rope.ReplaceRanges([]Edit[int]{{0, 1, []int{-1, -2}}, {50, 50, []int{-3}}, {99, 100, nil}})
+	assertValue(t, changed.SubRope(0, 4), []int{-1, -2, 1, 2})
+	assertValue(t, changed.SubRope(50, 53), []int{49, -3, 50})
+	assert(t, changed.Length() == 101, "Wrong length:", changed.Length())
+	leaves := map[*int]bool{}
+	for _, leaf := range rope.Leaves() {
+		leaves[&leaf[0]] = true
+	}
+	shared := 0
+	for _, leaf := range changed.Leaves() {
+		if leaves[&leaf[0]] {
+			shared++
+		}
+	}
+	assert(t, shared >= len(leaves) - 4, "Untouched leaves copied:", shared, len(leaves))
+
+	assert(t, rope.ReplaceRanges(nil) == rope, "Copied without edits")
+	assertPanics(t, ErrIndexOutOfRange, func() {
+		rope.ReplaceRanges([]Edit[int]{{10, 20, nil}, {15, 16, nil}})
+	})
+}
+
 func TestDelete(t *testing.T) {
 	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
 	rope := NewRope(originalValue, testSettings)
@@ -92,6 +164,35 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestRemoveLeaves(t *testing.T) {
+	settings := *testSettings
+	settings.Metrics = NewMetrics()
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, &settings)
+
+	changed := rope.Remove(4, 12) // The two middle leaves
+	assertValue(t, changed, []int{0, 1, 2, 3, 12, 13, 14, 15})
+	assert(t, settings.Metrics.Snapshot().BytesCopied == 0, "Leaves were copied")
+	assert(t, changed.left == rope.left.left && changed.right == rope.right.right, "Leaves not reused")
+	assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate())
+	assertValue(t, rope.Remove(0, 16), []int{})
+	assert(t, settings.Metrics.Snapshot().BytesCopied == 0, "Leaves were copied")
+}
+
+func TestRemoveCollapses(t *testing.T) {
+	settings := *testSettings
+	settings.JoinLength = 0 // Split nodes are never joined, however short
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, &settings)
+
+	changed := rope.Remove(1, 16).Remove(0, 1)
+	assertValue(t, changed, []int{})
+	assert(t, changed.value != nil, "Empty nodes kept:", changed.DebugString(4))
+	changed = rope.Remove(2, 8)
+	assertValue(t, changed, []int{0, 1, 8, 9, 10, 11, 12, 13, 14, 15})
+	assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate())
+	linked := link(Empty[int](&settings), rope)
+	assert(t, linked.Validate() == nil && linked.Depth() == rope.Depth(), "Empty side kept:", linked.DebugString(4))
+}
+
 func TestSlice(t *testing.T) {
 	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
 	rope := NewRope(originalValue, testSettings)
@@ -103,6 +204,30 @@ func TestSlice(t *testing.T) {
 	})
 }
 
+func TestAppendValue(t *testing.T) {
+	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	rope := NewRope(originalValue, testSettings)
+
+	buffer := make([]int, 0, 16)
+	buffer = rope.AppendValue(buffer)
+	buffer = rope.AppendSlice(buffer, 2, 5)
+
+	assert(t, cap(buffer) == 16, "Buffer was reallocated")
+	assertValue(t, NewRope(buffer, testSettings), []int {
+		0, 1, 2, 3, 4, 5, 6, 7, 2, 3, 4,
+	})
+}
+
+func TestGetMany(t *testing.T) {
+	originalValue := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	rope := NewRope(originalValue, testSettings).Insert(2, []int{-1, -2, -3})
+	values := rope.GetMany([]int{10, 0, 3, 3, 7})
+
+	assertValue(t, NewRope(values, testSettings), []int {
+		7, 0, -2, -2, 4,
+	})
+}
+
 func TestRebalance(t *testing.T) {
 	const n = 1000
 	originalValue := []int{}
@@ -124,6 +249,93 @@ func TestRebalance(t *testing.T) {
 	assertSameValue(t, balancedRope, newRope)
 }
 
+func TestHooks(t *testing.T) {
+	splits, joins, rebalances := 0, 0, 0
+	settings := *testSettings
+	settings.JoinLength = 3 // Otherwise, nodes short enough to join have an empty side, which is dropped
+	settings.OnSplit = func(left, right int) {
+		assert(t, left + right > settings.SplitLength, "Split a short leaf:", left, right)
+		splits++
+	}
+	settings.OnJoin = func(length int) {
+		assert(t, length < settings.JoinLength, "Joined a long node:", length)
+		joins++
+	}
+	settings.OnRebalance = func(length int) {
+		rebalances++
+	}
+
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	assert(t, splits == 1, "Wrong number of splits:", splits)
+	rope = rope.Remove(1, 7)
+	assert(t, joins == 1, "Wrong number of joins:", joins)
+	for i := 0; i < 10; i++ {
+		rope = rope.Insert(0, []int{0, 1, 2})
+	}
+	rope.Rebalance()
+	assert(t, rebalances > 0, "Rebalance not reported")
+}
+
+func TestSplitAt(t *testing.T) {
+	settings := *testSettings
+	settings.SplitAt = func(leaf []int) int {
+		return len(leaf) - 1 // Biased to the right, and clamped when 0
+	}
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	assertValue(t, rope, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	assert(t, rope.left.length == 7, "Split not used:", rope.left.length)
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+
+	settings.SplitAt = func(leaf []int) int { return 0 }
+	rope = NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	assert(t, rope.left.length == 1, "Split not clamped:", rope.left.length)
+	assertValue(t, rope, []int{0, 1, 2, 3, 4, 5, 6, 7})
+}
+
+func TestEmpty(t *testing.T) {
+	empties := []*Rope[int]{
+		Empty[int](testSettings),
+		NewRope[int](nil, testSettings),
+		NewPieceRope[int](nil, testSettings),
+		NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings).Remove(0, 8),
+	}
+	for _, rope := range empties {
+		assert(t, rope.IsEmpty() && rope.Length() == 0, "Not empty:", rope.Length())
+		assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+		assertValue(t, rope, []int{})
+		assertValue(t, rope.Remove(0, 0), []int{})
+		assertValue(t, rope.Replace(0, nil), []int{})
+		assert(t, len(rope.Slice(0, 0)) == 0, "Slice not empty")
+		assert(t, rope.walk(0, 0, func([]int) bool { return false }), "Visited a leaf")
+		rope.Rebalance()
+		assertValue(t, rope.Insert(0, []int{1}), []int{1})
+		assertValue(t, rope.Append([]int{1, 2}), []int{1, 2})
+	}
+	assert(t, !NewRope([]int{1}, testSettings).IsEmpty(), "Empty with an element")
+}
+
+func TestBulk(t *testing.T) {
+	settings := *testSettings
+	settings.Rebalance = 1.5
+	value := make([]int, 1000)
+	for i := range value {
+		value[i] = i
+	}
+	rope := NewRope(value, &settings)
+	assertValue(t, rope, value)
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+	assert(t, rope.IsBalanced() && rope.Depth() == rope.IdealDepth(), "Not balanced:", rope.Depth())
+
+	small := NewRope([]int{0, 1, 2, 3}, &settings)
+	inserted := small.Insert(2, value)
+	assertValue(t, inserted, append([]int{0, 1}, append(append([]int{}, value...), 2, 3)...))
+	assert(t, inserted.Validate() == nil, "Invalid rope:", inserted.Validate())
+	leaf, leafStart := inserted.leafAt(0)
+	assert(t, leafStart == 0 && &leaf.value[0] == &small.value[0], "Leaf copied")
+	value[0] = -1
+	assert(t, inserted.At(2) == 0, "Insertion not copied")
+}
+
 var inputs = []int{1, 10, 100, 1000, 10000, 100000}
 
 func BenchmarkRopeInsert(b *testing.B) {
@@ -179,3 +391,87 @@ func BenchmarkStringInsert(b *testing.B) {
 		})
 	}
 }
+
+func TestDefaultSettingsFor(t *testing.T) {
+	type big struct{ a, b, c, d int64 }
+	bytes := DefaultSettingsFor[byte]()
+	assert(t, bytes.SplitLength == 400 && bytes.JoinLength == 200, "Bytes not default:", *bytes)
+	assert(t, bytes != DefaultSettings, "Defaults not copied")
+	settings := DefaultSettingsFor[big]()
+	assert(t, settings.SplitLength == 400 / 32 && settings.JoinLength == 200 / 32, "Wrong lengths:", *settings)
+	assert(t, DefaultSettingsFor[[1024]byte]().SplitLength == 2, "Not clamped:", *DefaultSettingsFor[[1024]byte]())
+	assert(t, DefaultSettingsFor[struct{}]().SplitLength == 400, "Zero size scaled")
+}
+
+// The read paths don't allocate, so they can be used in render loops
+func TestReadsDontAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("The race detector allocates")
+	}
+	rope := NewRope(make([]int, 1000), testSettings).Insert(500, []int{1, 2, 3})
+	buffer := make([]int, 100)
+	sum := 0
+	reads := map[string]func() {
+		"At": func() { sum += rope.At(501) },
+		"CopySlice": func() { rope.CopySlice(buffer, 450, 550) },
+		"AppendSlice": func() { buffer = rope.AppendSlice(buffer[:0], 450, 550) },
+		"ForEachRange": func() {
+			rope.ForEachRange(450, 550, func(chunk []int) bool {
+				sum += len(chunk)
+				return true
+			})
+		},
+		"Chunks": func() {
+			for chunk := range rope.Chunks(450, 550) {
+				sum += len(chunk)
+			}
+		},
+		"All": func() {
+			for _, value := range rope.All(450, 550) {
+				sum += value
+			}
+		},
+	}
+	for name, read := range reads {
+		allocations := testing.AllocsPerRun(100, read)
+		assert(t, allocations == 0, name, "allocated", allocations, "times")
+	}
+}
+
+func BenchmarkReads(b *testing.B) {
+	rope := NewRope(make([]int, 100000), DefaultSettings)
+	for i := 0; i < 1000; i++ {
+		rope = rope.Insert((i * 7919) % rope.Length(), []int{i})
+	}
+	buffer := make([]int, 1000)
+	sum := 0
+	b.Run("At", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sum += rope.At((i * 7919) % rope.Length())
+		}
+	})
+	b.Run("CopySlice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rope.CopySlice(buffer, 50000, 51000)
+		}
+	})
+	b.Run("ForEachRange", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rope.ForEachRange(50000, 51000, func(chunk []int) bool {
+				sum += len(chunk)
+				return true
+			})
+		}
+	})
+	b.Run("Chunks", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for chunk := range rope.Chunks(50000, 51000) {
+				sum += len(chunk)
+			}
+		}
+	})
+}
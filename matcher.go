@@ -0,0 +1,126 @@
+package rope
+
+import "iter"
+
+// A pattern preprocessed for Boyer-Moore-Horspool search, so repeated
+// searches of the same pattern, like find-as-you-type, don't redo it
+type Matcher struct {
+	pattern []byte
+	skip    [256]int // How far to shift when the byte under the last position mismatches
+}
+
+func CompilePattern(pattern []byte) *Matcher {
+	m := &Matcher{pattern: append([]byte{}, pattern...)}
+	for i := range m.skip {
+		m.skip[i] = len(pattern)
+	}
+	for i := 0; i < len(pattern) - 1; i++ {
+		m.skip[pattern[i]] = len(pattern) - 1 - i
+	}
+	return m
+}
+
+func (m *Matcher) Pattern() []byte {
+	return m.pattern
+}
+
+// Returns the offset of the first match starting at from or later, or -1.
+// The from is checked like an insertion index, so it can be Length().
+func (m *Matcher) Find(r *Rope[byte], from int) int {
+	from = r.mustIndex(from, r.length)
+	if len(m.pattern) == 0 {
+		return from
+	}
+	last := len(m.pattern) - 1
+	cursor := r.Cursor()
+	for position := from; position + last < r.length; {
+		i := last
+		for i >= 0 && cursor.at(position + i) == m.pattern[i] {
+			i--
+		}
+		if i < 0 {
+			return position
+		}
+		position += m.skip[cursor.at(position + last)]
+	}
+	return -1
+}
+
+// A match of one of the patterns of a MultiMatcher
+type Match struct {
+	Pattern int // Index in the patterns compiled
+	Offset  int
+}
+
+// Patterns compiled into an Aho-Corasick automaton, to find them all
+// in a single scan of the rope
+type MultiMatcher struct {
+	patterns [][]byte
+	next     [][256]int32 // Transitions of the states, 0 being the initial one
+	outputs  [][]int      // Patterns ending at each state, longest first
+}
+
+func CompilePatterns(patterns [][]byte) *MultiMatcher {
+	m := &MultiMatcher{next: make([][256]int32, 1), outputs: make([][]int, 1)}
+	for i, pattern := range patterns {
+		m.patterns = append(m.patterns, append([]byte{}, pattern...))
+		if len(pattern) == 0 {
+			continue
+		}
+		state := int32(0)
+		for _, b := range pattern {
+			if m.next[state][b] == 0 {
+				m.next = append(m.next, [256]int32{})
+				m.outputs = append(m.outputs, nil)
+				m.next[state][b] = int32(len(m.next) - 1)
+			}
+			state = m.next[state][b]
+		}
+		m.outputs[state] = append(m.outputs[state], i)
+	}
+
+	// Breadth first, so the failure of a state is done before the state.
+	// Missing transitions are taken from the failure, making it a DFA.
+	failure := make([]int32, len(m.next))
+	queue := []int32{}
+	for b := range m.next[0] {
+		if child := m.next[0][b]; child != 0 {
+			queue = append(queue, child)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		m.outputs[state] = append(m.outputs[state], m.outputs[failure[state]]...)
+		for b := range m.next[state] {
+			child := m.next[state][b]
+			if child == 0 {
+				m.next[state][b] = m.next[failure[state]][b]
+				continue
+			}
+			failure[child] = m.next[failure[state]][b]
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Yields every match of every pattern, including overlapping ones,
+// ordered by where they end. Empty patterns never match.
+func (m *MultiMatcher) FindAll(r *Rope[byte]) iter.Seq[Match] {
+	return func(yield func(Match) bool) {
+		state, offset := int32(0), 0
+		r.walk(0, r.length, func(chunk []byte) bool {
+			for _, b := range chunk {
+				offset++
+				state = m.next[state][b]
+				for _, pattern := range m.outputs[state] {
+					if !yield(Match{pattern, offset - len(m.patterns[pattern])}) {
+						return false
+					}
+				}
+			}
+			return true
+		})
+	}
+}
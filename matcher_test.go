@@ -0,0 +1,57 @@
+package rope
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	text := "the rope is a rope of ropes, roped"
+	rope := NewRope([]byte(text), testSettings)
+	for _, pattern := range []string{"rope", "ropes", "roped", "e", "the rope", "ed", "pea", "x"} {
+		matcher := CompilePattern([]byte(pattern))
+		for from := 0; from <= len(text); from++ {
+			expected := strings.Index(text[from:], pattern)
+			if expected >= 0 {
+				expected += from
+			}
+			found := matcher.Find(rope, from)
+			assert(t, found == expected, "Wrong match of", pattern, "from", from, "got", found, "expected", expected)
+		}
+	}
+	assert(t, CompilePattern(nil).Find(rope, 3) == 3, "Empty pattern didn't match")
+	assertPanics(t, ErrIndexOutOfRange, func() {
+		CompilePattern([]byte("rope")).Find(rope, len(text) + 1)
+	})
+}
+
+func TestMultiMatcher(t *testing.T) {
+	text := "ushers said she is his"
+	rope := NewRope([]byte(text), testSettings)
+	patterns := []string{"he", "she", "his", "hers", "", "s"}
+	compiled := [][]byte{}
+	for _, pattern := range patterns {
+		compiled = append(compiled, []byte(pattern))
+	}
+	matcher := CompilePatterns(compiled)
+
+	found := map[Match]bool{}
+	for match := range matcher.FindAll(rope) {
+		pattern := patterns[match.Pattern]
+		assert(t, strings.HasPrefix(text[match.Offset:], pattern), "Wrong match:", match)
+		assert(t, !found[match], "Repeated match:", match)
+		found[match] = true
+	}
+	expected := 0
+	for _, pattern := range patterns {
+		if pattern != "" {
+			expected += strings.Count(text, pattern) // None of them overlap themselves
+		}
+	}
+	assert(t, len(found) == expected, "Wrong number of matches:", len(found), expected)
+
+	for match := range matcher.FindAll(rope) {
+		assert(t, match == Match{5, 1}, "Wrong first match:", match)
+		break
+	}
+}
@@ -0,0 +1,42 @@
+package rope
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrConflict = errors.New("rope: conflicting edits")
+
+// Returns base with the changes from it to ours and to theirs, found
+// with Diff, combined. Fails with ErrConflict if they change overlapping
+// ranges, or insert at the same offset, in different ways. Elements are
+// compared as set in the Settings.Eq of base.
+func Merge3[T any](base, ours, theirs *Rope[T]) (*Rope[T], error) {
+	patch, err := mergePatches(Diff(base, ours), Diff(base, theirs), equalFunc[T](base.settings))
+	if err != nil {
+		return nil, err
+	}
+	return patch.Apply(base), nil
+}
+
+// Returns the edits of both patches on the same rope, in order
+func mergePatches[T any](ours, theirs Patch[T], equal func(a, b T) bool) (Patch[T], error) {
+	merged := Patch[T]{}
+	for len(ours) > 0 || len(theirs) > 0 {
+		if len(theirs) == 0 || (len(ours) > 0 && ours[0].End <= theirs[0].Start && ours[0].Start < theirs[0].Start) {
+			merged, ours = append(merged, ours[0]), ours[1:]
+			continue
+		}
+		if len(ours) == 0 || (theirs[0].End <= ours[0].Start && theirs[0].Start < ours[0].Start) {
+			merged, theirs = append(merged, theirs[0]), theirs[1:]
+			continue
+		}
+		if ours[0].Start != theirs[0].Start || ours[0].End != theirs[0].End || !slices.EqualFunc(ours[0].With, theirs[0].With, equal) {
+			return nil, fmt.Errorf("%w: [%d, %d) and [%d, %d)",
+				ErrConflict, ours[0].Start, ours[0].End, theirs[0].Start, theirs[0].End)
+		}
+		merged, ours, theirs = append(merged, ours[0]), ours[1:], theirs[1:] // The same edit
+	}
+	return merged, nil
+}
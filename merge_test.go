@@ -0,0 +1,25 @@
+package rope
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	ours := base.Replace(1, []int{-1}).Insert(4, []int{10})
+	theirs := base.Remove(6, 8).Insert(0, []int{-2})
+
+	merged, err := Merge3(base, ours, theirs)
+	assert(t, err == nil, "Merge failed:", err)
+	assertValue(t, merged, []int{-2, 0, -1, 2, 3, 10, 4, 5})
+
+	same, err := Merge3(base, ours, ours)
+	assert(t, err == nil, "Same edits conflict:", err)
+	assertSameValue(t, same, ours)
+
+	_, err = Merge3(base, base.Replace(3, []int{-3}), base.Remove(2, 5))
+	assert(t, errors.Is(err, ErrConflict), "Overlapping edits merged:", err)
+	_, err = Merge3(base, base.Insert(3, []int{-3}), base.Insert(3, []int{-4}))
+	assert(t, errors.Is(err, ErrConflict), "Insertions at the same offset merged:", err)
+}
@@ -0,0 +1,76 @@
+package rope
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Metadata of a leaf in a range, from Metadata
+type LeafMetadata struct {
+	Start int // Of the leaf, which may start before the range
+	End   int
+	Value any
+}
+
+// Attaches a value for the key to the leaf containing the index, like
+// a cache of something computed from it. The leaf keeps it in every
+// version sharing it, and as leaves are never modified, the versions
+// where it was rewritten by an edit (or a rebuild, like Rebalance) lose it.
+// Keys are compared like map keys, and work best as unexported types.
+func (r *Rope[T]) SetMetadata(index int, key, value any) {
+	index = r.mustIndex(index, r.length - 1)
+	leaf, _ := r.leafAt(index)
+	setSummary(leaf, key, value)
+}
+
+// Returns the values of the key attached to the leaves in the range
+func (r *Rope[T]) Metadata(start, end int, key any) []LeafMetadata {
+	start, end = r.mustRange(start, end)
+	metadata := []LeafMetadata{}
+	r.collectMetadata(start, end, 0, key, &metadata)
+	return metadata
+}
+
+func (r *Rope[T]) collectMetadata(start, end, offset int, key any, metadata *[]LeafMetadata) {
+	if start == end {
+		return
+	}
+	if r.value != nil {
+		if value, ok := findSummary(r, key); ok {
+			*metadata = append(*metadata, LeafMetadata{offset, offset + r.length, value})
+		}
+		return
+	}
+	leftStart, leftEnd := bound(start, end, r.left.length)
+	r.left.collectMetadata(leftStart, leftEnd, offset, key, metadata)
+	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
+	r.right.collectMetadata(rightStart, rightEnd, offset + r.left.length, key, metadata)
+}
+
+func findSummary[T any](r *Rope[T], key any) (any, bool) {
+	for cached := (*summary)(atomic.LoadPointer(&r.summaries)); cached != nil; cached = cached.next {
+		if cached.key == key {
+			return cached.value, true
+		}
+	}
+	return nil, false
+}
+
+// Replaces the summary for the key, unlike cachedSummary,
+// copying the list without the previous one
+func setSummary[T any](r *Rope[T], key, value any) {
+	for {
+		head := (*summary)(atomic.LoadPointer(&r.summaries))
+		changed := &summary{key: key, value: value}
+		last := changed
+		for cached := head; cached != nil; cached = cached.next {
+			if cached.key != key {
+				last.next = &summary{key: cached.key, value: cached.value}
+				last = last.next
+			}
+		}
+		if atomic.CompareAndSwapPointer(&r.summaries, unsafe.Pointer(head), unsafe.Pointer(changed)) {
+			return
+		}
+	}
+}
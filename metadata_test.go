@@ -0,0 +1,25 @@
+package rope
+
+import "testing"
+
+type tokensKey struct{}
+
+func TestMetadata(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, testSettings) // Leaves of 3
+	rope.SetMetadata(1, tokensKey{}, "first")
+	rope.SetMetadata(7, tokensKey{}, "third")
+	rope.SetMetadata(8, tokensKey{}, "replaced")
+	rope.SetMetadata(9, "other key", 1)
+
+	metadata := rope.Metadata(2, 12, tokensKey{})
+	assert(t, len(metadata) == 2, "Wrong metadata:", metadata)
+	assert(t, metadata[0] == LeafMetadata{0, 3, "first"}, "Wrong metadata:", metadata[0])
+	assert(t, metadata[1] == LeafMetadata{6, 9, "replaced"}, "Wrong metadata:", metadata[1])
+	assert(t, len(rope.Metadata(3, 6, tokensKey{})) == 0, "Metadata on a leaf without it")
+
+	edited := rope.Replace(1, []int{-1})
+	metadata = edited.Metadata(0, 12, tokensKey{})
+	assert(t, len(metadata) == 1 && metadata[0].Value == "replaced", "Rewritten leaf kept metadata:", metadata)
+	assert(t, len(rope.Metadata(0, 12, tokensKey{})) == 2, "Original lost metadata")
+	assert(t, len(edited.Metadata(0, 12, "other key")) == 1, "Shared leaf lost metadata")
+}
@@ -0,0 +1,56 @@
+package rope
+
+import (
+	"expvar"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Counts what the ropes using it in their settings do, for observability
+// in long-running services. It is safe for concurrent use.
+type Metrics struct {
+	operations     atomic.Int64
+	nodesAllocated atomic.Int64
+	rebalances     atomic.Int64
+	bytesCopied    atomic.Int64
+}
+
+type MetricsSnapshot struct {
+	Operations     int64 // Calls to Insert, Remove, Replace, Append and Rebalance
+	NodesAllocated int64
+	Rebalances     int64 // Nodes rebuilt by Rebalance
+	BytesCopied    int64 // Copied into new leaves by the operations
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Operations:     m.operations.Load(),
+		NodesAllocated: m.nodesAllocated.Load(),
+		Rebalances:     m.rebalances.Load(),
+		BytesCopied:    m.bytesCopied.Load(),
+	}
+}
+
+// Exposes the snapshot as an expvar variable, which panics if the name is in use
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return m.Snapshot()
+	}))
+}
+
+func progress(settings *Settings, done, total int) {
+	if settings.OnProgress != nil {
+		settings.OnProgress(done, total)
+	}
+}
+
+func (r *Rope[T]) countCopied(elements int) {
+	if r.settings.Metrics != nil {
+		var element T
+		r.settings.Metrics.bytesCopied.Add(int64(elements) * int64(unsafe.Sizeof(element)))
+	}
+}
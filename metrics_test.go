@@ -0,0 +1,73 @@
+package rope
+
+import (
+	"bytes"
+	"expvar"
+	"io"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	settings := *testSettings
+	settings.Metrics = NewMetrics()
+	settings.Metrics.Publish("rope_test_metrics")
+
+	rope := NewRope([]int64{0, 1, 2, 3}, &settings)
+	rope = rope.Insert(1, []int64{-1}).Remove(0, 1)
+	for i := 0; i < 10; i++ {
+		rope = rope.Insert(0, []int64{0, 1, 2})
+	}
+	rope.Rebalance()
+	snapshot := settings.Metrics.Snapshot()
+
+	assert(t, snapshot.Operations == 13, "Wrong operation count:", snapshot.Operations)
+	assert(t, snapshot.NodesAllocated > 13, "Too few nodes allocated:", snapshot.NodesAllocated)
+	assert(t, snapshot.Rebalances > 0, "Rebalance not counted")
+	assert(t, snapshot.BytesCopied >= 8 * (5 + 4), "Too few bytes copied:", snapshot.BytesCopied)
+	assert(t, expvar.Get("rope_test_metrics") != nil, "Metrics not published")
+}
+
+func TestProgress(t *testing.T) {
+	type report struct{ done, total int }
+	reports := []report{}
+	settings := *testSettings
+	settings.OnProgress = func(done, total int) {
+		reports = append(reports, report{done, total})
+	}
+	assertProgress := func(operation string, total int) {
+		assert(t, len(reports) > 0, operation, "didn't report progress")
+		for i := 1; i < len(reports); i++ {
+			assert(t, reports[i].done > reports[i - 1].done, operation, "went backwards:", reports)
+		}
+		last := reports[len(reports) - 1]
+		assert(t, last == report{50, total}, operation, "didn't finish:", last)
+		reports = reports[:0]
+	}
+
+	rope := Empty[int](&settings)
+	for i := 0; i < 50; i++ {
+		rope = rope.Insert(0, []int{i})
+	}
+	rope.Rebalance()
+	assertProgress("Rebalance", 50)
+
+	buffer := &bytes.Buffer{}
+	must(rope.WriteChunks(buffer))
+	assertProgress("WriteChunks", 50)
+	_, err := ReadChunks[int](buffer, &settings)
+	must(err)
+	assertProgress("ReadChunks", -1)
+
+	must(EncodeDelta(rope, rope.Insert(10, []int{-1}).Remove(0, 1), buffer))
+	assertProgress("EncodeDelta", 50)
+
+	Diff(rope, rope.Insert(10, []int{-1}).Remove(0, 1))
+	assertProgress("Diff", 50)
+	Diff(rope, rope)
+	assertProgress("Diff of equal ropes", 50)
+
+	text := NewRope(bytes.Repeat([]byte("x"), 50), &settings)
+	_, err = NewReader(text).WriteTo(io.Discard)
+	must(err)
+	assertProgress("WriteTo", 50)
+}
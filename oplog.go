@@ -0,0 +1,72 @@
+package rope
+
+import "sync"
+
+type LogEntry struct {
+	Operation string // Name of the method, like "Insert"
+	Start     int    // Range of the operation, in the resulting rope for insertions
+	End       int
+	Length    int    // Length of the resulting rope
+	Depth     int    // Depth of the resulting rope
+}
+
+// Keeps the last operations done on the ropes using it in their
+// settings, to diagnose pathological usage patterns.
+type OpLog struct {
+	mutex   sync.Mutex
+	entries []LogEntry
+	next    int  // Where the next entry is written
+	full    bool // Whether the entries have wrapped around
+}
+
+func NewOpLog(size int) *OpLog {
+	return &OpLog{entries: make([]LogEntry, size)}
+}
+
+// Returns the logged operations, the oldest first
+func (l *OpLog) Entries() []LogEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.full {
+		return append([]LogEntry{}, l.entries[:l.next]...)
+	}
+	return append(append([]LogEntry{}, l.entries[l.next:]...), l.entries[:l.next]...)
+}
+
+func (l *OpLog) record(entry LogEntry) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if len(l.entries) == 0 {
+		return
+	}
+	l.entries[l.next] = entry
+	l.next++
+	if l.next == len(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// Returns the entries of the log in the settings, or nil if there is none
+func (r *Rope[T]) DebugLog() []LogEntry {
+	if r.settings.Log == nil {
+		return nil
+	}
+	return r.settings.Log.Entries()
+}
+
+func (r *Rope[T]) logOperation(operation string, start, end int) {
+	if r.settings.Metrics != nil {
+		r.settings.Metrics.operations.Add(1)
+	}
+	if r.settings.Log == nil {
+		return
+	}
+	r.settings.Log.record(LogEntry{
+		Operation: operation,
+		Start:     start,
+		End:       end,
+		Length:    r.length,
+		Depth:     r.Depth(),
+	})
+}
@@ -0,0 +1,21 @@
+package rope
+
+import "testing"
+
+func TestOpLog(t *testing.T) {
+	settings := *testSettings
+	settings.Log = NewOpLog(3)
+
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings)
+	rope = rope.Insert(2, []int{-1, -2}).Remove(0, 1).Append([]int{8})
+	entries := rope.DebugLog()
+	assert(t, len(entries) == 3, "Wrong number of entries:", entries)
+	assert(t, entries[0] == LogEntry{"Insert", 2, 4, 10, 3}, "Wrong entry:", entries[0])
+	assert(t, entries[1] == LogEntry{"Remove", 0, 1, 9, 3}, "Wrong entry:", entries[1])
+
+	rope.Rebalance()
+	entries = rope.DebugLog()
+	assert(t, len(entries) == 3, "Wrong number of entries:", entries)
+	assert(t, entries[0].Operation == "Remove", "Oldest entry not dropped:", entries)
+	assert(t, entries[2] == LogEntry{"Rebalance", 0, 10, 10, rope.Depth()}, "Wrong entry:", entries[2])
+}
@@ -0,0 +1,55 @@
+package rope
+
+type Option func(*options)
+
+type options struct {
+	settings  Settings
+	copyInput bool
+}
+
+// Creates a rope with DefaultSettingsFor[T], changed by the options.
+// Unlike NewRope, each rope gets its own copy of the settings.
+func New[T any](value []T, opts ...Option) *Rope[T] {
+	options := options{settings: *DefaultSettingsFor[T]()}
+	for _, option := range opts {
+		option(&options)
+	}
+	if options.copyInput {
+		value = append([]T{}, value...)
+	}
+	return NewRope(value, &options.settings)
+}
+
+// Also sets JoinLength to half of it, which WithJoinLength can override
+func WithSplitLength(splitLength int) Option {
+	return func(o *options) {
+		o.settings.SplitLength = splitLength
+		o.settings.JoinLength = splitLength / 2
+	}
+}
+
+func WithJoinLength(joinLength int) Option {
+	return func(o *options) {
+		o.settings.JoinLength = joinLength
+	}
+}
+
+func WithRebalance(ratio float32) Option {
+	return func(o *options) {
+		o.settings.Rebalance = ratio
+	}
+}
+
+func WithAutoRebalance() Option {
+	return func(o *options) {
+		o.settings.AutoRebalance = true
+	}
+}
+
+// Copies the value, so the caller can keep modifying it.
+// Otherwise, the rope references it, and it must not be modified.
+func WithCopyInput() Option {
+	return func(o *options) {
+		o.copyInput = true
+	}
+}
@@ -0,0 +1,47 @@
+package rope
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	value := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	rope := New(value, WithSplitLength(4), WithCopyInput())
+	value[0] = -1
+
+	assertValue(t, rope, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	assert(t, rope.settings.SplitLength == 4 && rope.settings.JoinLength == 2, "Wrong settings:", *rope.settings)
+	assert(t, rope.settings.Rebalance == DefaultSettings.Rebalance, "Default not kept:", *rope.settings)
+	assert(t, New(value).settings != New(value).settings, "Settings shared")
+	assert(t, New(value, WithSplitLength(4), WithJoinLength(1)).settings.JoinLength == 1, "Join not overriden")
+}
+
+func TestAutoRebalance(t *testing.T) {
+	rope := New([]int{}, WithSplitLength(4), WithRebalance(1.5), WithAutoRebalance())
+	for i := 0; i < 200; i++ {
+		rope = rope.Insert(0, []int{0, 1, 2})
+	}
+	assert(t, rope.Depth() <= rope.IdealDepth() + 2, "Not rebalanced:", rope.Depth(), rope.IdealDepth())
+	assert(t, rope.Validate() == nil, "Invalid rope:", rope.Validate())
+}
+
+func TestAutoRebalanceKeepsVersions(t *testing.T) {
+	unbalanced := New([]int{}, WithSplitLength(4), WithRebalance(1.5))
+	for i := 0; i < 50; i++ {
+		unbalanced = unbalanced.Insert(0, []int{i, i, i})
+	}
+	settings := *unbalanced.settings
+	settings.AutoRebalance = true
+	base := unbalanced.WithSettings(&settings)
+	value := base.Value()
+	assert(t, !base.left.IsBalanced(), "Nothing to rebalance")
+	edits := []func() *Rope[int]{
+		func() *Rope[int] { return base.Remove(base.left.length, base.length) }, // Returns base.left
+		func() *Rope[int] { return base.Remove(0, 3) },
+		func() *Rope[int] { return base.Insert(0, []int{-1}) },
+	}
+	for _, edit := range edits {
+		edited := edit()
+		assert(t, edited.Validate() == nil, "Invalid rope:", edited.Validate())
+		assert(t, base.Validate() == nil, "Original modified:", base.Validate())
+		assertValue(t, base, value)
+	}
+}
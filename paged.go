@@ -0,0 +1,361 @@
+package rope
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrBlockNotFound = errors.New("rope: block not found")
+
+// Each node is stored as a block, addressed by the SHA-256 of its content,
+// so the nodes shared by versions, or by documents, are stored once:
+//
+//	leaf:  0, encoded elements
+//	split: 1, uvarint left length, uvarint right length, left id, right id
+const (
+	pagedLeaf  = 0
+	pagedSplit = 1
+)
+
+type BlockID [sha256.Size]byte
+
+func (id BlockID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Keeps blocks by their id, like a directory or a key-value store. As a
+// block always has the same content, Put can skip blocks it already has.
+// Stores are used as keys of the summaries of the nodes saved to them, so
+// they must be comparable, like pointers.
+type BlockStore interface {
+	Get(id BlockID) ([]byte, error) // Fails with ErrBlockNotFound if there is none
+	Put(id BlockID, block []byte) error
+}
+
+// Marks the nodes already saved to the store, with their id
+type savedBlockKey struct {
+	store BlockStore
+}
+
+// Writes the nodes of the rope to the store, and returns the id of the
+// root, to open it with OpenPaged. The nodes are marked once saved, so
+// saving a new version only writes the nodes it doesn't share.
+func SaveBlocks[T any](r *Rope[T], store BlockStore) (BlockID, error) {
+	key := savedBlockKey{store}
+	if id, ok := findSummary(r, key); ok {
+		return id.(BlockID), nil
+	}
+	var block []byte
+	if r.value != nil {
+		encoded, err := encodeElements(r.value)
+		if err != nil {
+			return BlockID{}, err
+		}
+		block = append([]byte{pagedLeaf}, encoded...)
+	} else {
+		left, err := SaveBlocks(r.left, store)
+		if err != nil {
+			return BlockID{}, err
+		}
+		right, err := SaveBlocks(r.right, store)
+		if err != nil {
+			return BlockID{}, err
+		}
+		block = binary.AppendUvarint([]byte{pagedSplit}, uint64(r.left.length))
+		block = binary.AppendUvarint(block, uint64(r.right.length))
+		block = append(append(block, left[:]...), right[:]...)
+	}
+	id := BlockID(sha256.Sum256(block))
+	if err := store.Put(id, block); err != nil {
+		return BlockID{}, err
+	}
+	setSummary(r, key, id)
+	return id, nil
+}
+
+// A rope saved by SaveBlocks, read from its store loading only the blocks
+// on the path to what is read, and keeping up to a number of them in
+// memory, the least recently used evicted first, so reads take O(log n)
+// blocks and memory is bounded. It is safe for concurrent use.
+type PagedRope[T any] struct {
+	store    BlockStore
+	root     BlockID
+	length   int
+	settings *Settings
+
+	mutex  sync.Mutex
+	cache  map[BlockID]*list.Element // Of the elements of recent
+	recent *list.List                // Of the cached nodes, the most recently used first
+	limit  int
+}
+
+type pagedNode[T any] struct {
+	id                 BlockID
+	values             []T // Of a leaf, nil for splits
+	left, right        BlockID
+	leftLength, length int
+}
+
+// Opens the rope with the root from the store, keeping up to cacheBlocks
+// blocks in memory, at least the path to the last read. Ropes loaded from
+// it use the settings.
+func OpenPaged[T any](store BlockStore, root BlockID, settings *Settings, cacheBlocks int) (*PagedRope[T], error) {
+	if settings == nil {
+		return nil, ErrNilSettings
+	}
+	paged := &PagedRope[T]{
+		store:    store,
+		root:     root,
+		settings: settings,
+		cache:    map[BlockID]*list.Element{},
+		recent:   list.New(),
+		limit:    max(cacheBlocks, 1),
+	}
+	node, err := paged.node(root)
+	if err != nil {
+		return nil, err
+	}
+	paged.length = node.length
+	return paged, nil
+}
+
+func (p *PagedRope[T]) Root() BlockID {
+	return p.root
+}
+
+func (p *PagedRope[T]) Length() int {
+	return p.length
+}
+
+// Returns the node of the block, from the cache or the store
+func (p *PagedRope[T]) node(id BlockID) (*pagedNode[T], error) {
+	p.mutex.Lock()
+	if element, ok := p.cache[id]; ok {
+		p.recent.MoveToFront(element)
+		p.mutex.Unlock()
+		return element.Value.(*pagedNode[T]), nil
+	}
+	p.mutex.Unlock()
+
+	block, err := p.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	node, err := decodeBlock[T](id, block)
+	if err != nil {
+		return nil, err
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.cache[id]; !ok { // Unless loaded concurrently
+		p.cache[id] = p.recent.PushFront(node)
+		for p.recent.Len() > p.limit {
+			delete(p.cache, p.recent.Remove(p.recent.Back()).(*pagedNode[T]).id)
+		}
+	}
+	return node, nil
+}
+
+func decodeBlock[T any](id BlockID, block []byte) (*pagedNode[T], error) {
+	if len(block) == 0 || BlockID(sha256.Sum256(block)) != id {
+		return nil, fmt.Errorf("%w: block %v corrupted", ErrInvalidEncoding, id)
+	}
+	node := &pagedNode[T]{id: id}
+	switch block[0] {
+	case pagedLeaf:
+		values, err := decodeElements[T](block[1:])
+		if err != nil {
+			return nil, err
+		}
+		node.values, node.length = values[:len(values):len(values)], len(values)
+		return node, nil
+	case pagedSplit:
+		reader := bytes.NewReader(block[1:])
+		leftLength, err := readUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		rightLength, err := readUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(reader, node.left[:]); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(reader, node.right[:]); err != nil {
+			return nil, err
+		}
+		node.leftLength, node.length = leftLength, leftLength + rightLength
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: unknown block kind %d", ErrInvalidEncoding, block[0])
+}
+
+func (p *PagedRope[T]) checkRange(start, end int) error {
+	if start < 0 || end > p.length {
+		return fmt.Errorf("%w: [%d, %d) with length %d", ErrIndexOutOfRange, start, end, p.length)
+	}
+	if start > end {
+		return fmt.Errorf("%w: [%d, %d)", ErrInvalidRange, start, end)
+	}
+	return nil
+}
+
+func (p *PagedRope[T]) At(index int) (T, error) {
+	var value T
+	if err := p.checkRange(index, index + 1); err != nil {
+		return value, err
+	}
+	id := p.root
+	for {
+		node, err := p.node(id)
+		if err != nil {
+			return value, err
+		}
+		if node.values != nil {
+			return node.values[index], nil
+		}
+		if index < node.leftLength {
+			id = node.left
+		} else {
+			id, index = node.right, index - node.leftLength
+		}
+	}
+}
+
+// Returns a copy of the range, loading only the blocks in it
+func (p *PagedRope[T]) Slice(start, end int) ([]T, error) {
+	if err := p.checkRange(start, end); err != nil {
+		return nil, err
+	}
+	values := make([]T, 0, end - start)
+	err := p.visit(p.root, start, end, func(leaf []T) {
+		values = append(values, leaf...)
+	})
+	return values, err
+}
+
+// Returns the range as a rope, loading only the blocks in it. Its leaves
+// share the elements in the cache, which are never modified.
+func (p *PagedRope[T]) Load(start, end int) (*Rope[T], error) {
+	if err := p.checkRange(start, end); err != nil {
+		return nil, err
+	}
+	leaves := []*Rope[T]{}
+	err := p.visit(p.root, start, end, func(leaf []T) {
+		leaves = append(leaves, NewRope(leaf, p.settings))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(leaves) == 0 {
+		return NewRope([]T{}, p.settings), nil
+	}
+	return fromLeaves(leaves, p.settings), nil
+}
+
+// Calls visit with the parts of the leaves in the range, in order
+func (p *PagedRope[T]) visit(id BlockID, start, end int, visit func(leaf []T)) error {
+	if start >= end {
+		return nil
+	}
+	node, err := p.node(id)
+	if err != nil {
+		return err
+	}
+	if node.values != nil {
+		visit(node.values[start:end:end])
+		return nil
+	}
+	leftStart, leftEnd := bound(start, end, node.leftLength)
+	if err := p.visit(node.left, leftStart, leftEnd, visit); err != nil {
+		return err
+	}
+	rightStart, rightEnd := bound(start - node.leftLength, end - node.leftLength, node.length - node.leftLength)
+	return p.visit(node.right, rightStart, rightEnd, visit)
+}
+
+// Keeps blocks in memory, mostly for tests
+type MemoryBlocks struct {
+	mutex  sync.Mutex
+	blocks map[BlockID][]byte
+}
+
+func NewMemoryBlocks() *MemoryBlocks {
+	return &MemoryBlocks{blocks: map[BlockID][]byte{}}
+}
+
+func (m *MemoryBlocks) Get(id BlockID) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	block, ok := m.blocks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrBlockNotFound, id)
+	}
+	return block, nil
+}
+
+func (m *MemoryBlocks) Put(id BlockID, block []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.blocks[id] = bytes.Clone(block)
+	return nil
+}
+
+func (m *MemoryBlocks) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.blocks)
+}
+
+// Keeps each block in a file of the directory, named by its id
+type DirBlocks struct {
+	dir string
+}
+
+// Opens the directory as a store, creating it if needed
+func NewDirBlocks(dir string) (*DirBlocks, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirBlocks{dir}, nil
+}
+
+func (d *DirBlocks) Get(id BlockID) ([]byte, error) {
+	block, err := os.ReadFile(filepath.Join(d.dir, id.String()))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v", ErrBlockNotFound, id)
+	}
+	return block, err
+}
+
+// Writes the block to a temporary file renamed to its name, so blocks
+// are never seen half written
+func (d *DirBlocks) Put(id BlockID, block []byte) error {
+	path := filepath.Join(d.dir, id.String())
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	file, err := os.CreateTemp(d.dir, "block-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // Fails once renamed
+	if _, err := file.Write(block); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
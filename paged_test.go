@@ -0,0 +1,65 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPagedRope(t *testing.T) {
+	text := make([]byte, 10000)
+	for i := range text {
+		text[i] = byte(i % 251)
+	}
+	rope := NewRope(text, DefaultSettings)
+	store := NewMemoryBlocks()
+	root, err := SaveBlocks(rope, store)
+	assert(t, err == nil, "Couldn't save:", err)
+	blocks := store.Len()
+	assert(t, blocks == rope.Stats().Nodes, "Wrong number of blocks:", blocks, rope.Stats().Nodes)
+
+	paged, err := OpenPaged[byte](store, root, DefaultSettings, 4)
+	assert(t, err == nil && paged.Length() == len(text), "Couldn't open:", err)
+	for _, index := range []int{0, 4321, 9999} {
+		value, err := paged.At(index)
+		assert(t, err == nil && value == text[index], "Wrong value at", index, value, err)
+	}
+	assert(t, paged.recent.Len() <= 4, "Too many blocks cached:", paged.recent.Len())
+	slice, err := paged.Slice(3000, 7000)
+	assert(t, err == nil && bytes.Equal(slice, text[3000:7000]), "Wrong slice:", err)
+	loaded, err := paged.Load(100, 9000)
+	assert(t, err == nil && loaded.Validate() == nil, "Couldn't load:", err)
+	assertValue(t, loaded, text[100:9000])
+	_, err = paged.At(len(text))
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+
+	edited := rope.Insert(5000, []byte("new"))
+	root, err = SaveBlocks(edited, store)
+	assert(t, err == nil, "Couldn't save:", err)
+	assert(t, store.Len() - blocks <= 2 * edited.Depth(), "Shared nodes saved again:", store.Len() - blocks)
+	paged, err = OpenPaged[byte](store, root, DefaultSettings, 4)
+	assert(t, err == nil, "Couldn't open:", err)
+	slice, _ = paged.Slice(4990, 5010)
+	assert(t, bytes.Equal(slice, edited.Slice(4990, 5010)), "Wrong slice after an edit:", slice)
+}
+
+func TestDirBlocks(t *testing.T) {
+	store, err := NewDirBlocks(t.TempDir())
+	assert(t, err == nil, "Couldn't create the store:", err)
+	rope := NewRope([]int32{1, 2, 3, 4, 5, 6, 7, 8, 9}, testSettings)
+	root, err := SaveBlocks(rope, store)
+	assert(t, err == nil, "Couldn't save:", err)
+	paged, err := OpenPaged[int32](store, root, testSettings, 16)
+	assert(t, err == nil, "Couldn't open:", err)
+	loaded, err := paged.Load(0, paged.Length())
+	assert(t, err == nil, "Couldn't load:", err)
+	assertSameValue(t, loaded, rope)
+
+	_, err = OpenPaged[int32](store, BlockID{}, testSettings, 16)
+	assert(t, errors.Is(err, ErrBlockNotFound), "Wrong error:", err)
+	os.WriteFile(filepath.Join(store.dir, root.String()), []byte("corrupted"), 0o644)
+	_, err = OpenPaged[int32](store, root, testSettings, 16)
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Wrong error:", err)
+}
@@ -0,0 +1,235 @@
+package rope
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Replaces the range [Start, End) with the values in With, so it is an
+// insertion if the range is empty, and a removal if With is.
+type Edit[T any] struct {
+	Start int
+	End   int
+	With  []T
+}
+
+// Edits on the same rope, with the offsets all in it, sorted by Start
+// and not overlapping, though adjacent ones can touch. Insertions at
+// the same offset are applied in order.
+type Patch[T any] []Edit[T]
+
+// Checks the patch can be applied to a rope of the length
+func (p Patch[T]) Validate(length int) error {
+	position := 0
+	for i, edit := range p {
+		if edit.Start > edit.End {
+			return fmt.Errorf("%w: edit %d replaces [%d, %d)", ErrInvalidRange, i, edit.Start, edit.End)
+		}
+		if edit.Start < position || edit.End > length {
+			return fmt.Errorf("%w: edit %d replaces [%d, %d) after %d with length %d",
+				ErrIndexOutOfRange, i, edit.Start, edit.End, position, length)
+		}
+		position = edit.End
+	}
+	return nil
+}
+
+// Applies the edits from the last one, so the offsets stay valid.
+// Panics if the patch isn't valid for the rope.
+func (p Patch[T]) Apply(r *Rope[T]) *Rope[T] {
+	must(p.Validate(r.length))
+	for i := len(p) - 1; i >= 0; i-- {
+		edit := p[i]
+		r = r.Remove(edit.Start, edit.End).Insert(edit.Start, edit.With)
+	}
+	return r
+}
+
+// Sorts the edits by Start, keeping the order of the ones at the same offset
+func (p Patch[T]) Sort() {
+	sort.SliceStable(p, func(a, b int) bool {
+		return p[a].Start < p[b].Start
+	})
+}
+
+// Returns the edit undoing edit, where against is the rope it was applied to
+func Invert[T any](edit Edit[T], against *Rope[T]) Edit[T] {
+	return Edit[T]{edit.Start, edit.Start + len(edit.With), against.Slice(edit.Start, edit.End)}
+}
+
+// Returns the edits undoing the patch, where against is the rope it was
+// applied to, with their offsets in the result
+func (p Patch[T]) Invert(against *Rope[T]) Patch[T] {
+	inverted := make(Patch[T], len(p))
+	shift := 0 // How much longer the result is before the edit
+	for i, edit := range p {
+		inverted[i] = Edit[T]{edit.Start + shift, edit.Start + shift + len(edit.With), against.Slice(edit.Start, edit.End)}
+		shift += len(edit.With) - (edit.End - edit.Start)
+	}
+	return inverted
+}
+
+// A part of the result of a patch: the range of the rope it was applied
+// to, or values it inserted
+type patchSegment[T any] struct {
+	start, end int
+	with       []T
+}
+
+func (s patchSegment[T]) length() int {
+	if s.with != nil {
+		return len(s.with)
+	}
+	return s.end - s.start
+}
+
+func (s patchSegment[T]) slice(start, end int) patchSegment[T] {
+	if s.with != nil {
+		return patchSegment[T]{with: s.with[start:end]}
+	}
+	return patchSegment[T]{start: s.start + start, end: s.start + end}
+}
+
+// Returns the patch doing a and then b, so b has its offsets in the result
+// of a, without needing the rope they are applied to
+func Compose[T any](a, b Patch[T]) Patch[T] {
+	// The result of a, ending with what is after the last edit
+	segments := []patchSegment[T]{}
+	position := 0
+	for _, edit := range a {
+		if edit.Start > position {
+			segments = append(segments, patchSegment[T]{start: position, end: edit.Start})
+		}
+		if len(edit.With) > 0 {
+			segments = append(segments, patchSegment[T]{with: edit.With})
+		}
+		position = edit.End
+	}
+	segments = append(segments, patchSegment[T]{start: position, end: math.MaxInt})
+
+	// The result of b, from the segments outside its edits
+	composed := []patchSegment[T]{}
+	offset := 0 // In the result of a, where the segment starts
+	for _, edit := range b {
+		for segments[0].length() <= edit.Start - offset { // The tail is never skipped
+			composed = append(composed, segments[0])
+			offset += segments[0].length()
+			segments = segments[1:]
+		}
+		if edit.Start > offset { // The segment starts before the edit
+			composed = append(composed, segments[0].slice(0, edit.Start - offset))
+			segments[0] = segments[0].slice(edit.Start - offset, segments[0].length())
+			offset = edit.Start
+		}
+		if len(edit.With) > 0 {
+			composed = append(composed, patchSegment[T]{with: edit.With})
+		}
+		for segments[0].length() <= edit.End - offset {
+			offset += segments[0].length()
+			segments = segments[1:]
+		}
+		if edit.End > offset { // The segment ends after the edit
+			segments[0] = segments[0].slice(edit.End - offset, segments[0].length())
+			offset = edit.End
+		}
+	}
+	composed = append(composed, segments...)
+
+	// The edits replacing what is between the ranges kept
+	patch := Patch[T]{}
+	position = 0
+	var with []T
+	for _, segment := range composed {
+		if segment.with != nil {
+			with = append(with, segment.with...)
+			continue
+		}
+		if segment.start > position || len(with) > 0 {
+			patch = patch.add(Edit[T]{position, segment.start, with})
+			with = nil
+		}
+		position = segment.end
+	}
+	return patch
+}
+
+// Which side of values inserted at an offset it is mapped to
+type Bias int
+
+const (
+	BiasLeft  Bias = iota // Before them, staying with the value before it
+	BiasRight             // After them, staying with the value after it
+)
+
+// Returns where the offset in a rope is after applying the edits, which
+// are sorted and don't overlap as in a Patch. Offsets inside a replaced
+// range, and at insertions, go to the side of the values inserted given by
+// the bias, while the ones at its ends stay next to the value kept.
+func MapOffset[T any](offset int, edits []Edit[T], bias Bias) int {
+	shift := 0 // How much longer the result is before the edit
+	for _, edit := range edits {
+		replaces := edit.Start < edit.End
+		switch {
+		case offset < edit.Start || (offset == edit.Start && replaces):
+			return offset + shift
+		case offset > edit.End || (offset == edit.End && replaces):
+			// Moved by the edit, and maybe by the next ones
+		case bias == BiasLeft:
+			return edit.Start + shift
+		case replaces: // Inside the range, with BiasRight
+			return edit.Start + shift + len(edit.With)
+		}
+		shift += len(edit.With) - (edit.End - edit.Start)
+	}
+	return offset + shift
+}
+
+// Returns the edits sorted by Start, with the ones overlapping or
+// touching merged into one replacing both ranges with their values in
+// order, and without the ones doing nothing, so they form a Patch.
+// The edits given are not modified.
+func NormalizeEdits[T any](edits []Edit[T]) Patch[T] {
+	sorted := slices.Clone(Patch[T](edits))
+	sorted.Sort()
+	normalized := Patch[T]{}
+	for _, edit := range sorted {
+		if edit.Start == edit.End && len(edit.With) == 0 {
+			continue
+		}
+		if last := len(normalized) - 1; last >= 0 && edit.Start <= normalized[last].End {
+			normalized[last].End = max(normalized[last].End, edit.End)
+			normalized[last].With = append(slices.Clip(normalized[last].With), edit.With...)
+			continue
+		}
+		normalized = append(normalized, edit)
+	}
+	return normalized
+}
+
+// Applies edits on the rope in any order, normalized by NormalizeEdits,
+// returning an error if any of them is out of range instead of panicking
+func (r *Rope[T]) ApplyEdits(edits []Edit[T]) (*Rope[T], error) {
+	for _, edit := range edits {
+		if err := (Patch[T]{edit}).Validate(r.length); err != nil {
+			return nil, err
+		}
+	}
+	return NormalizeEdits(edits).Apply(r), nil
+}
+
+// Appends the edit, after the last one, joining them if they are closer
+// than SplitLength, with the values of r between them, so applying many
+// small edits copies each leaf once, and the leaves far from them are
+// shared
+func (p Patch[T]) join(r *Rope[T], edit Edit[T]) Patch[T] {
+	last := len(p) - 1
+	if last < 0 || edit.Start - p[last].End >= r.settings.SplitLength {
+		return append(p, edit)
+	}
+	p[last].With = append(p[last].With, r.Slice(p[last].End, edit.Start)...)
+	p[last].With = append(p[last].With, edit.With...)
+	p[last].End = edit.End
+	return p
+}
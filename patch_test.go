@@ -0,0 +1,126 @@
+package rope
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	patch := Patch[int]{
+		{Start: 6, End: 8, With: nil},
+		{Start: 0, End: 0, With: []int{-1}},
+		{Start: 0, End: 2, With: []int{-2}},
+		{Start: 3, End: 4, With: []int{30, 31}},
+	}
+	patch.Sort()
+	assert(t, patch[0].Start == 0 && patch[0].End == 0 && patch[3].Start == 6, "Wrong order:", patch)
+	assertValue(t, patch.Apply(rope), []int{-1, -2, 2, 30, 31, 4, 5})
+	assertValue(t, rope, []int{0, 1, 2, 3, 4, 5, 6, 7})
+
+	err := Patch[int]{{Start: 2, End: 5}, {Start: 4, End: 6}}.Validate(8)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Overlapping edits accepted:", err)
+	err = Patch[int]{{Start: 5, End: 2}}.Validate(8)
+	assert(t, errors.Is(err, ErrInvalidRange), "Reversed edit accepted:", err)
+	assertPanics(t, ErrIndexOutOfRange, func() { Patch[int]{{Start: 7, End: 9}}.Apply(rope) })
+}
+
+// Returns sorted edits on a rope of the length, with values from next
+func randomPatch(random *rand.Rand, length int, next *int) Patch[int] {
+	patch := Patch[int]{}
+	position := 0
+	for position < length && random.Intn(4) > 0 {
+		start := position + random.Intn(length - position + 1)
+		end := start + random.Intn(min(3, length - start) + 1)
+		with := make([]int, random.Intn(3))
+		for i := range with {
+			*next++
+			with[i] = *next
+		}
+		patch = append(patch, Edit[int]{start, end, with})
+		position = end
+	}
+	return patch
+}
+
+func TestInvert(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	edit := Edit[int]{2, 5, []int{-1}}
+	inverted := Invert(edit, rope)
+	assert(t, inverted.Start == 2 && inverted.End == 3, "Wrong range:", inverted)
+	assertValue(t, Patch[int]{inverted}.Apply(Patch[int]{edit}.Apply(rope)), rope.Value())
+
+	random := rand.New(rand.NewSource(1))
+	next := 100
+	for i := 0; i < 100; i++ {
+		patch := randomPatch(random, rope.Length(), &next)
+		assertSameValue(t, patch.Invert(rope).Apply(patch.Apply(rope)), rope)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	next := 100
+	for i := 0; i < 300; i++ {
+		rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+		a := randomPatch(random, rope.Length(), &next)
+		middle := a.Apply(rope)
+		b := randomPatch(random, middle.Length(), &next)
+		composed := Compose(a, b)
+		assert(t, composed.Validate(rope.Length()) == nil, "Invalid patch:", composed.Validate(rope.Length()), a, b, composed)
+		assertSameValue(t, composed.Apply(rope), b.Apply(middle))
+	}
+	assert(t, len(Compose(Patch[int]{}, Patch[int]{})) == 0, "Edits from nothing")
+}
+
+func TestMapOffset(t *testing.T) {
+	patch := Patch[int]{
+		{Start: 1, End: 1, With: []int{-1, -2}}, // Insertion
+		{Start: 1, End: 1, With: []int{-3}},
+		{Start: 3, End: 6, With: []int{-4}}, // Replacement
+		{Start: 7, End: 8, With: nil},       // Removal
+	}
+	// The result is 0 -1 -2 -3 1 2 -4 6
+	cases := []struct {
+		offset, left, right int
+	}{
+		{0, 0, 0},
+		{1, 1, 4},
+		{2, 5, 5},
+		{3, 6, 6},
+		{4, 6, 7},
+		{6, 7, 7},
+		{7, 8, 8},
+		{8, 8, 8},
+	}
+	for _, c := range cases {
+		left, right := MapOffset(c.offset, patch, BiasLeft), MapOffset(c.offset, patch, BiasRight)
+		assert(t, left == c.left && right == c.right, "Wrong offsets for", c.offset, ":", left, right)
+	}
+	assertValue(t, patch.Apply(NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)), []int{0, -1, -2, -3, 1, 2, -4, 6})
+}
+
+func TestNormalizeEdits(t *testing.T) {
+	edits := []Edit[int]{
+		{Start: 6, End: 7, With: []int{-3}},
+		{Start: 1, End: 3, With: []int{-1}},
+		{Start: 2, End: 4, With: []int{-2}}, // Overlaps the last one
+		{Start: 5, End: 5, With: nil},       // Does nothing
+		{Start: 7, End: 7, With: []int{-4}}, // Touches the first one
+	}
+	normalized := NormalizeEdits(edits)
+	assert(t, len(normalized) == 2, "Wrong edits:", normalized)
+	assert(t, normalized[0].Start == 1 && normalized[0].End == 4 && len(normalized[0].With) == 2, "Wrong edit:", normalized[0])
+	assert(t, normalized[1].Start == 6 && normalized[1].End == 7 && len(normalized[1].With) == 2, "Wrong edit:", normalized[1])
+	assert(t, edits[0].Start == 6 && len(edits[1].With) == 1, "Edits modified")
+
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	changed, err := rope.ApplyEdits(edits)
+	assert(t, err == nil, "ApplyEdits failed:", err)
+	assertValue(t, changed, []int{0, -1, -2, 4, 5, -3, -4, 7})
+	_, err = rope.ApplyEdits([]Edit[int]{{Start: 3, End: 9}})
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Edit out of range applied:", err)
+	_, err = rope.ApplyEdits([]Edit[int]{{Start: 3, End: 2}})
+	assert(t, errors.Is(err, ErrInvalidRange), "Reversed edit applied:", err)
+}
@@ -0,0 +1,53 @@
+package rope
+
+// Creates a rope referencing the original value as a single piece, which
+// is only split along the paths that get edited, so opening a huge
+// document allocates almost nothing. The original must not be modified
+// while the rope, or any rope derived from it, is in use.
+func NewPieceRope[T any](original []T, settings *Settings) *Rope[T] {
+	return newPiece(original, settings)
+}
+
+func newPiece[T any](value []T, settings *Settings) *Rope[T] {
+	if value == nil { // A nil value would mark the leaf as split
+		value = []T{}
+	}
+	piece := newNode[T](settings)
+	piece.value = value
+	piece.length = len(value)
+	piece.piece = len(value) > settings.SplitLength // Otherwise, it is a regular leaf
+	return piece
+}
+
+// Splits a piece in halves, without copying, so it can be edited
+func (r *Rope[T]) expand() *Rope[T] {
+	expanded := newNode[T](r.settings)
+	expanded.length = r.length
+	split := r.splitPoint()
+	expanded.left = newPiece(r.value[:split:split], r.settings)
+	expanded.right = newPiece(r.value[split:r.length:r.length], r.settings)
+	expanded.height = 1
+	return expanded
+}
+
+// Removes by slicing the leaf around the range instead of copying what
+// is left. There are no tombstones: the sides are leaves of their own,
+// and the removed elements stay in the backing array they view until
+// Compact copies them out of it, as they don't own it.
+func (r *Rope[T]) removeLazily(start, end int) *Rope[T] {
+	r.disown()
+	before := r.value[:start:start]
+	after := r.value[end:r.length:r.length]
+	if len(before) == 0 {
+		return newPiece(after, r.settings)
+	}
+	if len(after) == 0 {
+		return newPiece(before, r.settings)
+	}
+	changed := newNode[T](r.settings)
+	changed.length = len(before) + len(after)
+	changed.left = newPiece(before, r.settings)
+	changed.right = newPiece(after, r.settings)
+	changed.adjust() // Joins if too short
+	return changed
+}
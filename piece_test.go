@@ -0,0 +1,56 @@
+package rope
+
+import "testing"
+
+func countNodes[T any](rope *Rope[T]) int {
+	if rope.value != nil {
+		return 1
+	}
+	return 1 + countNodes(rope.left) + countNodes(rope.right)
+}
+
+func TestPieceRope(t *testing.T) {
+	originalValue := make([]int, 1 << 16)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	rope := NewPieceRope(originalValue, testSettings)
+	assert(t, countNodes(rope) == 1, "Piece rope was split eagerly")
+
+	newRope := rope.Insert(1000, []int{-1}).Remove(2000, 2005).Replace(3000, []int{-2, -3})
+	expected := append([]int{}, originalValue...)
+	expected = append(expected[:1000], append([]int{-1}, expected[1000:]...)...)
+	expected = append(expected[:2000], expected[2005:]...)
+	copy(expected[3000:], []int{-2, -3})
+
+	assertValue(t, rope, originalValue)
+	assertValue(t, newRope, expected)
+	assert(t, countNodes(newRope) < 200, "Too many nodes materialized:", countNodes(newRope))
+}
+
+func TestLazyRemove(t *testing.T) {
+	settings := *DefaultSettings
+	settings.LazyRemove = true
+
+	originalValue := make([]int, 1000)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	rope := NewRope(originalValue, &settings)
+	newRope := rope
+	expected := append([]int{}, originalValue...)
+	for i := 0; i < 100; i++ {
+		index := (i * 7919) % newRope.Length()
+		newRope = newRope.Remove(index, index + 3)
+		expected = append(expected[:index], expected[index + 3:]...)
+	}
+
+	assertValue(t, rope, originalValue)
+	assertValue(t, newRope, expected)
+	compacted := newRope.Compact()
+	assertValue(t, compacted, expected)
+	for _, leaf := range leavesOf(compacted) {
+		assert(t, &leaf[0] != &originalValue[leaf[0]], "Removed elements still retained")
+	}
+	assertValue(t, newRope.Remove(10, 690), append(append([]int{}, expected[:10]...), expected[690:]...))
+}
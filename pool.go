@@ -0,0 +1,129 @@
+package rope
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Recycles the nodes and leaf arrays of released ropes, for long-lived
+// processes opening and closing many documents. It can be shared by many
+// ropes, but only ropes of a single element type benefit at a time.
+type Pool struct {
+	Limit  int // Maximum of nodes and of arrays kept, unlimited if 0
+	mutex  sync.Mutex
+	nodes  any // []*Rope[T] ready to be reused
+	values any // [][]T ready to be reused, with no length
+}
+
+func NewPool(limit int) *Pool {
+	return &Pool{Limit: limit}
+}
+
+// Gives the nodes and leaf arrays of the rope back to the pool of its
+// settings, if any. The caller guarantees that neither the rope, nor any
+// version sharing nodes with it are used again. Only the arrays the rope
+// owns outright are released: those its edits allocated for a single
+// leaf, which no other leaf has been sliced from since, so versions
+// sharing arrays but not nodes with it are safe. Arrays with spare
+// capacity claimed for appends, and the slices given to NewRope, are left
+// to the garbage collector. Panics with ErrFrozen if the rope is frozen,
+// as it is meant to be shared.
+func (r *Rope[T]) Release() {
+	if r.frozen {
+		panic(ErrFrozen)
+	}
+	pool := r.settings.Pool
+	if pool == nil {
+		return
+	}
+	nodes := []*Rope[T]{}
+	values := [][]T{}
+	seen := map[*Rope[T]]bool{}
+	var release func(node *Rope[T])
+	release = func(node *Rope[T]) {
+		if node == nil || seen[node] {
+			return
+		}
+		seen[node] = true
+		release(node.left)
+		release(node.right)
+		if node.value != nil && node.claim == nil && atomic.LoadUint32(&node.owned) == 1 {
+			values = append(values, node.value[:0])
+		}
+		nodes = append(nodes, node)
+	}
+	release(r)
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pooledNodes, _ := pool.nodes.([]*Rope[T])
+	for _, node := range nodes {
+		if pool.Limit > 0 && len(pooledNodes) >= pool.Limit {
+			break
+		}
+		*node = Rope[T]{}
+		pooledNodes = append(pooledNodes, node)
+	}
+	pooledValues, _ := pool.values.([][]T)
+	for _, value := range values {
+		if pool.Limit > 0 && len(pooledValues) >= pool.Limit {
+			break
+		}
+		pooledValues = append(pooledValues, value)
+	}
+	pool.nodes, pool.values = pooledNodes, pooledValues
+}
+
+// Returns a leaf with the value, an array allocated for it alone, which
+// Release can give back to the pool unless the leaf had to be split
+func ownedLeaf[T any](value []T, settings *Settings) *Rope[T] {
+	leaf := NewRope(value, settings)
+	if leaf.value != nil {
+		leaf.owned = 1
+	}
+	return leaf
+}
+
+// Marks the leaf as sharing its array with another one, so Release keeps
+// it out of the pool. As the leaf may be shared by versions, it is atomic.
+func (r *Rope[T]) disown() {
+	if atomic.LoadUint32(&r.owned) != 0 {
+		atomic.StoreUint32(&r.owned, 0)
+	}
+}
+
+// Returns a released node, or nil if there are none of the type
+func takeNode[T any](p *Pool) *Rope[T] {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	nodes, _ := p.nodes.([]*Rope[T])
+	if len(nodes) == 0 {
+		return nil
+	}
+	p.nodes = nodes[:len(nodes) - 1]
+	return nodes[len(nodes) - 1]
+}
+
+// Arrays checked for one long enough before allocating a new one
+const poolSearch = 8
+
+// Returns an array of the given length for a leaf, reusing a released
+// one from the pool of the settings if it is long enough
+func newLeafValue[T any](settings *Settings, length int) []T {
+	if settings.Pool == nil {
+		return make([]T, length)
+	}
+	pool := settings.Pool
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	values, _ := pool.values.([][]T)
+	for i := len(values) - 1; i >= max(0, len(values) - poolSearch); i-- {
+		if cap(values[i]) >= length {
+			value := values[i][:length]
+			values[i] = values[len(values) - 1]
+			pool.values = values[:len(values) - 1]
+			return value
+		}
+	}
+	return make([]T, length)
+}
@@ -0,0 +1,81 @@
+package rope
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	settings := *testSettings
+	settings.Pool = NewPool(0)
+
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, &settings).Remove(1, 2).Remove(4, 5)
+	nodes := rope.Stats().Nodes
+	rope.Release()
+	assert(t, len(settings.Pool.nodes.([]*Rope[int])) == nodes, "Nodes not released")
+	assert(t, len(settings.Pool.values.([][]int)) == 2, "Leaves not released:", settings.Pool.values)
+
+	reused := NewRope([]int{0, 1, 2}, &settings).Insert(3, []int{3, 4}).Replace(0, []int{-1})
+	assertValue(t, reused, []int{-1, 1, 2, 3, 4})
+	assert(t, len(settings.Pool.nodes.([]*Rope[int])) < nodes, "Nodes not reused")
+	assert(t, len(settings.Pool.values.([][]int)) < 2, "Leaves not reused")
+	assert(t, reused.Validate() == nil, "Invalid rope:", reused.Validate())
+}
+
+func TestPoolSharedLeaves(t *testing.T) {
+	settings := *testSettings
+	settings.Pool = NewPool(1)
+
+	leaf := NewRope([]int{0, 1, 2, 3, 4}, &settings).Remove(4, 5)
+	link(leaf, leaf).Release()
+	assert(t, len(settings.Pool.nodes.([]*Rope[int])) == 1, "Pool limit not kept")
+	assert(t, len(settings.Pool.values.([][]int)) == 1, "Shared leaf released twice")
+
+	value := []int{0, 1, 2, 3, 4, 5}
+	settings.Pool = NewPool(0)
+	link(NewRope(value[:4], &settings), NewRope(value[2:], &settings)).Release()
+	assert(t, len(settings.Pool.values.([][]int)) == 0, "Slices given to NewRope released")
+
+	assertPanics(t, ErrFrozen, func() { NewRope(value, &settings).Freeze().Release() })
+	NewRope(value, testSettings).Release() // Without a pool, does nothing
+}
+
+// Versions sharing arrays but not nodes with a released one keep them
+func TestPoolSharedArrays(t *testing.T) {
+	settings := *testSettings
+	settings.Pool = NewPool(0)
+
+	base := Empty[int](&settings).Append([]int{1, 2})
+	appended := base.Append([]int{3})
+	appended.Release()
+	NewRope([]int{7, 8, 9}, &settings).Remove(0, 1)
+	assertValue(t, base, []int{1, 2})
+
+	edited := NewRope([]int{0, 1, 2, 3, 4}, &settings).Remove(4, 5)
+	sub := edited.SubRope(1, 3)
+	edited.Release()
+	NewRope([]int{7, 8, 9, 10}, &settings).Remove(0, 1)
+	assertValue(t, sub, []int{1, 2})
+	assert(t, len(settings.Pool.values.([][]int)) == 0, "Sliced array released")
+
+	edited = NewRope([]int{0, 1, 2, 3, 4}, &settings).Remove(4, 5)
+	leaves := edited.Leaves()
+	edited.Release()
+	NewRope([]int{7, 8, 9, 10}, &settings).Remove(0, 1)
+	assert(t, leaves[0][0] == 0, "Array of Leaves released:", leaves)
+}
+
+// The leaves a delta copies from the old rope keep their arrays once it is released
+func TestPoolDelta(t *testing.T) {
+	settings := *testSettings
+	settings.Pool = NewPool(0)
+
+	old := NewRope([]int{9, 1, 2, 3, 4}, &settings).Remove(4, 5)
+	var delta bytes.Buffer
+	must(EncodeDelta(old, old.Append([]int{7, 7, 7, 7, 7}), &delta))
+	applied, err := ApplyDelta(old, &delta)
+	assert(t, err == nil, "Unexpected error:", err)
+	old.Release()
+	NewRope([]int{0, -5, 0}, &settings).Remove(0, 1) // Reuses the array of [2, 3]
+	assertValue(t, applied, []int{9, 1, 2, 3, 7, 7, 7, 7, 7})
+}
@@ -0,0 +1,70 @@
+package rope
+
+import (
+	"math/rand"
+	"reflect"
+)
+
+// Implements testing/quick.Generator, making ropes of random content,
+// settings and shapes, including unbalanced ones, for property testing.
+// Elements that aren't numbers, booleans or strings are left as zero.
+// Importing testing/quick here would add its flags to every program.
+func (r *Rope[T]) Generate(random *rand.Rand, size int) reflect.Value {
+	values := make([]T, random.Intn(size + 1))
+	for i := range values {
+		randomize(reflect.ValueOf(&values[i]).Elem(), random)
+	}
+
+	splitLength := 2 + random.Intn(15)
+	settings := &Settings{
+		SplitLength: splitLength,
+		JoinLength:  splitLength / 2,
+		Rebalance:   1.5,
+	}
+
+	var rope *Rope[T]
+	switch random.Intn(3) {
+	case 0: // Balanced
+		rope = NewRope(values, settings)
+	case 1: // Built from insertions at random places
+		rope = NewRope([]T{}, settings)
+		for start := 0; start < len(values); {
+			end := start + 1 + random.Intn(splitLength)
+			if end > len(values) {
+				end = len(values)
+			}
+			rope = rope.Insert(random.Intn(rope.Length() + 1), values[start:end])
+			start = end
+		}
+	default: // Built by prepending, leaning to the left
+		rope = NewRope([]T{}, settings)
+		for end := len(values); end > 0; {
+			start := end - 1 - random.Intn(splitLength)
+			if start < 0 {
+				start = 0
+			}
+			rope = rope.Insert(0, values[start:end])
+			end = start
+		}
+	}
+	return reflect.ValueOf(rope)
+}
+
+func randomize(value reflect.Value, random *rand.Rand) {
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(random.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(random.Int63() - random.Int63())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value.SetUint(random.Uint64())
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(random.NormFloat64())
+	case reflect.String:
+		runes := make([]rune, random.Intn(8))
+		for i := range runes {
+			runes[i] = rune(' ' + random.Intn(95))
+		}
+		value.SetString(string(runes))
+	}
+}
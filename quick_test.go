@@ -0,0 +1,29 @@
+package rope
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestGenerate(t *testing.T) {
+	valid := func(rope *Rope[int], index int) bool {
+		if index < 0 {
+			index = -index
+		}
+		index %= rope.Length() + 1
+		inserted := rope.Insert(index, []int{1, 2, 3})
+		return rope.Validate() == nil && inserted.Validate() == nil &&
+			inserted.Length() == rope.Length() + 3 && len(rope.Value()) == rope.Length()
+	}
+	if err := quick.Check(valid, nil); err != nil {
+		t.Error(err)
+	}
+
+	shapes := map[bool]bool{}
+	check := func(rope *Rope[byte]) bool {
+		shapes[rope.IsBalanced()] = true
+		return true
+	}
+	quick.Check(check, &quick.Config{MaxCount: 200})
+	assert(t, shapes[true] && shapes[false], "Shapes aren't varied:", shapes)
+}
@@ -0,0 +1,126 @@
+package rope
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+)
+
+// An operation of a delta in the format of Quill and other web editors,
+// where lengths count UTF-16 code units, as in JavaScript strings
+type quillOp struct {
+	Retain int             `json:"retain,omitempty"`
+	Insert json.RawMessage `json:"insert,omitempty"`
+	Delete int             `json:"delete,omitempty"`
+}
+
+type quillDelta struct {
+	Ops []quillOp `json:"ops"`
+}
+
+// Marshals the patch on the UTF-8 text as a Quill delta of retain, insert
+// and delete operations, using the text to count the lengths in UTF-16.
+// Each edit is an insertion followed by a deletion, as Quill orders them.
+func MarshalQuillDelta(p Patch[byte], text *Rope[byte]) ([]byte, error) {
+	if err := p.Validate(text.length); err != nil {
+		return nil, err
+	}
+	delta := quillDelta{Ops: []quillOp{}}
+	position := 0
+	for _, edit := range p {
+		if edit.Start > position {
+			delta.Ops = append(delta.Ops, quillOp{Retain: utf16Length(text, position, edit.Start)})
+		}
+		if len(edit.With) > 0 {
+			insert, err := json.Marshal(string(edit.With))
+			if err != nil {
+				return nil, err
+			}
+			delta.Ops = append(delta.Ops, quillOp{Insert: insert})
+		}
+		if edit.End > edit.Start {
+			delta.Ops = append(delta.Ops, quillOp{Delete: utf16Length(text, edit.Start, edit.End)})
+		}
+		position = edit.End
+	}
+	return json.Marshal(delta)
+}
+
+// Unmarshals a Quill delta on the UTF-8 text as a patch. Attributes are
+// ignored, and only text can be inserted, not embeds. Lengths ending
+// inside a character that needs two UTF-16 units include all of it.
+func UnmarshalQuillDelta(data []byte, text *Rope[byte]) (Patch[byte], error) {
+	delta := quillDelta{}
+	if err := json.Unmarshal(data, &delta); err != nil {
+		return nil, err
+	}
+	patch := Patch[byte]{}
+	reader := bufio.NewReader(NewReader(text))
+	position := 0
+	for i, op := range delta.Ops {
+		switch {
+		case op.Insert != nil:
+			insert := ""
+			if err := json.Unmarshal(op.Insert, &insert); err != nil {
+				return nil, fmt.Errorf("%w: insert %d isn't text", ErrInvalidEncoding, i)
+			}
+			if last := len(patch) - 1; last >= 0 && patch[last].End == position {
+				patch[last].With = append(patch[last].With, insert...)
+			} else {
+				patch = append(patch, Edit[byte]{Start: position, End: position, With: []byte(insert)})
+			}
+		case op.Retain > 0:
+			length, err := skipUTF16(reader, op.Retain)
+			if err != nil {
+				return nil, fmt.Errorf("%w: retain %d past the end", ErrIndexOutOfRange, i)
+			}
+			position += length
+		case op.Delete > 0:
+			length, err := skipUTF16(reader, op.Delete)
+			if err != nil {
+				return nil, fmt.Errorf("%w: delete %d past the end", ErrIndexOutOfRange, i)
+			}
+			if last := len(patch) - 1; last >= 0 && patch[last].End == position {
+				patch[last].End += length
+			} else {
+				patch = append(patch, Edit[byte]{Start: position, End: position + length})
+			}
+			position += length
+		default:
+			return nil, fmt.Errorf("%w: empty operation %d", ErrInvalidEncoding, i)
+		}
+	}
+	return patch, nil
+}
+
+func utf16Length(text *Rope[byte], start, end int) int {
+	units := 0
+	reader := bufio.NewReader(NewSectionReader(text, start, end))
+	for {
+		char, _, err := reader.ReadRune()
+		if err != nil {
+			return units
+		}
+		units++
+		if char >= 0x10000 {
+			units++
+		}
+	}
+}
+
+// Reads characters until that many UTF-16 code units are read,
+// returning the length in bytes
+func skipUTF16(reader *bufio.Reader, units int) (length int, err error) {
+	for units > 0 {
+		char, size, err := reader.ReadRune()
+		if err != nil {
+			return length, err
+		}
+		length += size
+		units--
+		if char >= 0x10000 {
+			units--
+		}
+	}
+	return length, nil
+}
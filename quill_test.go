@@ -0,0 +1,28 @@
+package rope
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuillDelta(t *testing.T) {
+	text := NewRope([]byte("héllo 🌍 world"), testSettings) // é is 2 bytes, 🌍 4 bytes and 2 units
+	patch := Patch[byte]{
+		{Start: 0, End: 6, With: []byte("Hi")},
+		{Start: 11, End: 11, With: []byte(",")},
+		{Start: 12, End: 17},
+	}
+	data, err := MarshalQuillDelta(patch, text)
+	expected := `{"ops":[{"insert":"Hi"},{"delete":5},{"retain":3},{"insert":","},{"retain":1},{"delete":5}]}`
+	assert(t, err == nil && string(data) == expected, "Wrong delta:", string(data), err)
+
+	unmarshaled, err := UnmarshalQuillDelta(data, text)
+	assert(t, err == nil, "Wrong delta:", err)
+	assert(t, string(unmarshaled.Apply(text).Value()) == "Hi 🌍, ", "Wrong patch:", string(unmarshaled.Apply(text).Value()))
+	assert(t, string(unmarshaled.Apply(text).Value()) == string(patch.Apply(text).Value()), "Patches differ")
+
+	_, err = UnmarshalQuillDelta([]byte(`{"ops":[{"retain":20}]}`), text)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	_, err = UnmarshalQuillDelta([]byte(`{"ops":[{"insert":{"image":"a.png"}}]}`), text)
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Wrong error:", err)
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package rope
+
+// The race detector allocates, so allocations aren't counted with it
+const raceEnabled = true
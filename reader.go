@@ -0,0 +1,85 @@
+package rope
+
+import (
+	"fmt"
+	"io"
+)
+
+// Reads a range of a byte rope, working like io.SectionReader
+type Reader struct {
+	rope   *Rope[byte]
+	start  int
+	end    int
+	offset int // From start, reads return io.EOF if past the end
+}
+
+func NewReader(r *Rope[byte]) *Reader {
+	return NewSectionReader(r, 0, r.length)
+}
+
+// Reads the range [start, end) of the rope, which is checked like in Slice
+func NewSectionReader(r *Rope[byte], start, end int) *Reader {
+	start, end = r.mustRange(start, end)
+	return &Reader{rope: r, start: start, end: end}
+}
+
+func (r *Reader) Size() int64 {
+	return int64(r.end - r.start)
+}
+
+func (r *Reader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadAt(p, int64(r.offset))
+	r.offset += n
+	if err == io.EOF && n > 0 {
+		err = nil // Returned by the next Read, as io.Reader prefers
+	}
+	return n, err
+}
+
+// The offset is from the start of the section
+func (r *Reader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("%w: offset %d", ErrIndexOutOfRange, off)
+	}
+	if off >= r.Size() {
+		return 0, io.EOF
+	}
+	start := r.start + int(off)
+	end := r.end
+	if len(p) < end - start {
+		end = start + len(p)
+	}
+	r.rope.copySlice(p, start, end)
+	if end - start < len(p) {
+		return end - start, io.EOF
+	}
+	return end - start, nil
+}
+
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(r.offset)
+	case io.SeekEnd:
+		offset += r.Size()
+	default:
+		return 0, fmt.Errorf("rope: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: position %d", ErrIndexOutOfRange, offset)
+	}
+	r.offset = int(offset)
+	return offset, nil
+}
+
+// Writes the rest of the section without copying it, reporting progress
+// to the OnProgress of the rope, in bytes of the rest
+func (r *Reader) WriteTo(w io.Writer) (int64, error) {
+	if r.offset >= r.end - r.start {
+		return 0, nil
+	}
+	n, err := WriteRangeTo(w, r.rope, r.start + r.offset, r.end)
+	r.offset += int(n)
+	return n, err
+}
@@ -0,0 +1,41 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSectionReader(t *testing.T) {
+	rope := NewRope([]byte("---\ntitle: rope\n---\nbody"), testSettings)
+	reader := NewSectionReader(rope, 4, 15)
+	var _ io.ReadSeeker = reader
+	var _ io.ReaderAt = reader
+
+	read, err := io.ReadAll(reader)
+	assert(t, err == nil && string(read) == "title: rope", "Wrong read:", err, string(read))
+	assert(t, reader.Size() == 11, "Wrong size:", reader.Size())
+
+	buffer := make([]byte, 4)
+	n, err := reader.ReadAt(buffer, 7)
+	assert(t, n == 4 && err == nil && string(buffer) == "rope", "Wrong read:", n, err, string(buffer))
+	n, err = reader.ReadAt(buffer, 9)
+	assert(t, n == 2 && err == io.EOF && string(buffer[:n]) == "pe", "Wrong read:", n, err)
+	_, err = reader.ReadAt(buffer, -1)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+
+	position, err := reader.Seek(-4, io.SeekEnd)
+	assert(t, position == 7 && err == nil, "Wrong seek:", position, err)
+	written := &bytes.Buffer{}
+	reader.WriteTo(written)
+	assert(t, written.String() == "rope", "Wrong write:", written.String())
+	_, err = reader.Seek(-1, io.SeekStart)
+	assert(t, errors.Is(err, ErrIndexOutOfRange), "Wrong error:", err)
+	reader.Seek(20, io.SeekCurrent)
+	n, err = reader.Read(buffer)
+	assert(t, n == 0 && err == io.EOF, "Read past the end:", n, err)
+
+	read, _ = io.ReadAll(NewReader(rope))
+	assert(t, string(read) == string(rope.Value()), "Wrong read:", string(read))
+}
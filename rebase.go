@@ -0,0 +1,50 @@
+package rope
+
+import "slices"
+
+// An edit of Rebase colliding with a change made since its version
+type Conflict[T any] struct {
+	Edit  Edit[T] // As given, in the offsets of the version it was made on
+	Other Edit[T] // The change it collides with, in the same offsets
+}
+
+// Returns the edits, made on the version from, moved to apply on the
+// version to, as when a client edited a version the server has since
+// changed. The changes between them are found with Diff, and edits
+// overlapping one, or inserting at the same offset, are left out and
+// returned as conflicts, like in Merge3, unless they make the same change.
+// Panics with ErrUnknownVersion if a version doesn't exist.
+func (d *Document[T]) Rebase(edits Patch[T], from, to VersionID) (Patch[T], []Conflict[T]) {
+	base := d.RopeAt(from)
+	changes := Diff(base, d.RopeAt(to))
+	equal := equalFunc[T](base.settings)
+	rebased := Patch[T]{}
+	conflicts := []Conflict[T]{}
+	for _, edit := range edits {
+		other, collides := collision(edit, changes)
+		if collides {
+			same := other.Start == edit.Start && other.End == edit.End && slices.EqualFunc(other.With, edit.With, equal)
+			if !same {
+				conflicts = append(conflicts, Conflict[T]{edit, other})
+			}
+			continue
+		}
+		// No change is inside the range, so it is moved as a whole
+		start := MapOffset(edit.Start, changes, BiasRight)
+		rebased = append(rebased, Edit[T]{start, start + edit.End - edit.Start, edit.With})
+	}
+	return rebased, conflicts
+}
+
+// Returns the first change overlapping the edit, or at the same offset
+func collision[T any](edit Edit[T], changes Patch[T]) (Edit[T], bool) {
+	for _, change := range changes {
+		if change.Start == edit.Start || (change.Start < edit.End && edit.Start < change.End) {
+			return change, true
+		}
+		if change.Start > edit.End {
+			break
+		}
+	}
+	return Edit[T]{}, false
+}
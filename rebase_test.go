@@ -0,0 +1,28 @@
+package rope
+
+import "testing"
+
+func TestRebase(t *testing.T) {
+	document := NewDocument(NewRope([]byte("hello big world"), testSettings))
+	client := document.Version()
+	document.Apply(Edit[byte]{0, 5, []byte("HELLO")})
+	document.Apply(Edit[byte]{6, 9, []byte("huge")})
+	latest := document.Version()
+
+	edits := Patch[byte]{
+		{0, 5, []byte("HELLO")}, // Made on both
+		{5, 5, []byte(",")},
+		{7, 8, []byte("a")}, // Inside "big", changed to "huge"
+		{10, 15, []byte("there")},
+		{15, 15, []byte("!")},
+	}
+	rebased, conflicts := document.Rebase(edits, client, latest)
+	assert(t, len(conflicts) == 1 && conflicts[0].Edit.Start == 7, "Wrong conflicts:", conflicts)
+	assert(t, conflicts[0].Other.Start <= 7 && conflicts[0].Other.End >= 8, "Wrong change in the conflict:", conflicts[0])
+	assertValue(t, rebased.Apply(document.Rope()), []byte("HELLO, huge there!"))
+	assert(t, len(rebased) == 3, "Same change rebased:", rebased)
+
+	rebased, conflicts = document.Rebase(edits[1:2], latest, latest)
+	assert(t, len(conflicts) == 0 && rebased[0].Start == 5, "Edits moved without changes:", rebased)
+	assertPanics(t, ErrUnknownVersion, func() { document.Rebase(edits, client, latest + 1) })
+}
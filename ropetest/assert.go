@@ -0,0 +1,70 @@
+package ropetest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+const diffContext = 4 // Elements shown around the first difference
+
+// Describes where got and want first differ, or returns "" if they are equal
+func DiffReport[T comparable](got, want []T) string {
+	offset := 0
+	for offset < len(got) && offset < len(want) && got[offset] == want[offset] {
+		offset++
+	}
+	if offset == len(got) && offset == len(want) {
+		return ""
+	}
+	start, end := offset - diffContext, offset + diffContext
+	if start < 0 {
+		start = 0
+	}
+	return fmt.Sprintf("first difference at offset %d, with lengths %d and %d\ngot:  %s\nwant: %s",
+		offset, len(got), len(want), window(got, start, end), window(want, start, end))
+}
+
+func window[T any](values []T, start, end int) string {
+	if end > len(values) {
+		end = len(values)
+	}
+	if start > end {
+		start = end
+	}
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "... "
+	}
+	if end < len(values) {
+		suffix = " ..."
+	}
+	return fmt.Sprintf("%s%v%s", prefix, values[start:end], suffix)
+}
+
+func AssertEqualValue[T comparable](t testing.TB, r *rope.Rope[T], want []T) {
+	t.Helper()
+	if diff := DiffReport(r.Value(), want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func AssertSameValue[T comparable](t testing.TB, a, b *rope.Rope[T]) {
+	t.Helper()
+	if diff := DiffReport(a.Value(), b.Value()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// Checks the rope is valid and Rebalance would leave it as it is
+func AssertBalanced[T any](t testing.TB, r *rope.Rope[T]) {
+	t.Helper()
+	if err := r.Validate(); err != nil {
+		t.Error(err)
+	}
+	if !r.IsBalanced() {
+		t.Errorf("unbalanced rope, with balance factor %v and depth %d, ideal %d",
+			r.BalanceFactor(), r.Depth(), r.IdealDepth())
+	}
+}
@@ -0,0 +1,50 @@
+package ropetest
+
+import (
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+func TestDiffReport(t *testing.T) {
+	if diff := DiffReport([]int{1, 2, 3}, []int{1, 2, 3}); diff != "" {
+		t.Error("Equal slices reported different:", diff)
+	}
+	expected := "first difference at offset 6, with lengths 10 and 10\n" +
+		"got:  ... [2 3 4 5 -1 7 8 9]\n" +
+		"want: ... [2 3 4 5 6 7 8 9]"
+	if diff := DiffReport([]int{0, 1, 2, 3, 4, 5, -1, 7, 8, 9}, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}); diff != expected {
+		t.Error("Wrong report:\n" + diff)
+	}
+	expected = "first difference at offset 2, with lengths 2 and 3\n" +
+		"got:  [0 1]\n" +
+		"want: [0 1 2]"
+	if diff := DiffReport([]int{0, 1}, []int{0, 1, 2}); diff != expected {
+		t.Error("Wrong report:\n" + diff)
+	}
+}
+
+// Records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.failed = true
+}
+
+func TestAssertions(t *testing.T) {
+	r := rope.NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, rope.DefaultSettings)
+	AssertEqualValue(t, r, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	AssertSameValue(t, r, r.Insert(0, []int{}))
+	AssertBalanced(t, r)
+
+	failing := &recorder{}
+	AssertEqualValue(failing, r, []int{0})
+	if !failing.failed {
+		t.Error("Difference not reported")
+	}
+}
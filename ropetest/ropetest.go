@@ -0,0 +1,148 @@
+// Package ropetest helps testing ropes and the code using them,
+// checking them against plain slices.
+package ropetest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+type Kind int
+
+const (
+	Insert Kind = iota
+	Remove
+	Replace
+	Append
+	Slice
+	Rebalance
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Insert:
+		return "Insert"
+	case Remove:
+		return "Remove"
+	case Replace:
+		return "Replace"
+	case Append:
+		return "Append"
+	case Slice:
+		return "Slice"
+	case Rebalance:
+		return "Rebalance"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+type Op[T any] struct {
+	Kind   Kind
+	Start  int // Index for Insert and Replace
+	End    int // Only for Remove and Slice
+	Values []T // Only for Insert, Replace and Append
+}
+
+func (o Op[T]) String() string {
+	switch o.Kind {
+	case Insert, Replace:
+		return fmt.Sprintf("%v(%d, %v)", o.Kind, o.Start, o.Values)
+	case Append:
+		return fmt.Sprintf("%v(%v)", o.Kind, o.Values)
+	case Remove, Slice:
+		return fmt.Sprintf("%v(%d, %d)", o.Kind, o.Start, o.End)
+	}
+	return fmt.Sprintf("%v()", o.Kind)
+}
+
+// Applies the operations, starting from an empty rope with the settings,
+// to both a rope and a slice, failing at the first difference between
+// them, or if the rope breaks its invariants or changes a previous version.
+func CheckOperations[T comparable](t testing.TB, ops []Op[T], settings *rope.Settings) {
+	t.Helper()
+	current := rope.NewRope([]T{}, settings)
+	model := []T{}
+	for i, op := range ops {
+		previous, previousModel := current, model
+		current, model = apply(t, current, model, op)
+		if err := current.Validate(); err != nil {
+			t.Fatalf("after op %d, %v: %v", i, op, err)
+		}
+		if diff := DiffReport(current.Value(), model); diff != "" {
+			t.Fatalf("after op %d, %v: %s", i, op, diff)
+		}
+		if op.Kind != Rebalance && DiffReport(previous.Value(), previousModel) != "" {
+			t.Fatalf("op %d, %v, changed the previous version", i, op)
+		}
+	}
+}
+
+func apply[T comparable](t testing.TB, current *rope.Rope[T], model []T, op Op[T]) (*rope.Rope[T], []T) {
+	t.Helper()
+	switch op.Kind {
+	case Insert:
+		changed := append(append(append([]T{}, model[:op.Start]...), op.Values...), model[op.Start:]...)
+		return current.Insert(op.Start, op.Values), changed
+	case Remove:
+		changed := append(append([]T{}, model[:op.Start]...), model[op.End:]...)
+		return current.Remove(op.Start, op.End), changed
+	case Replace:
+		changed := append([]T{}, model...)
+		copy(changed[op.Start:], op.Values)
+		return current.Replace(op.Start, op.Values), changed
+	case Append:
+		return current.Append(op.Values), append(append([]T{}, model...), op.Values...)
+	case Slice:
+		if diff := DiffReport(current.Slice(op.Start, op.End), model[op.Start:op.End]); diff != "" {
+			t.Fatalf("%v: %s", op, diff)
+		}
+		return current, model
+	case Rebalance:
+		return current.Rebalanced(), model
+	}
+	t.Fatalf("unknown op %v", op)
+	return current, model
+}
+
+// Generates valid operations for CheckOperations, with up to maxValues
+// values made by value in each.
+func RandomOps[T any](random *rand.Rand, count, maxValues int, value func(*rand.Rand) T) []Op[T] {
+	ops := make([]Op[T], count)
+	length := 0
+	for i := range ops {
+		op := Op[T]{Kind: Kind(random.Intn(int(Rebalance) + 1))}
+		if length == 0 && op.Kind != Insert && op.Kind != Append {
+			op.Kind = Append
+		}
+		values := make([]T, 1 + random.Intn(maxValues))
+		for j := range values {
+			values[j] = value(random)
+		}
+		switch op.Kind {
+		case Insert:
+			op.Start = random.Intn(length + 1)
+			op.Values = values
+			length += len(values)
+		case Append:
+			op.Values = values
+			length += len(values)
+		case Replace:
+			op.Start = random.Intn(length)
+			if len(values) > length - op.Start {
+				values = values[:length - op.Start]
+			}
+			op.Values = values
+		case Remove, Slice:
+			op.Start = random.Intn(length + 1)
+			op.End = op.Start + random.Intn(length - op.Start + 1)
+			if op.Kind == Remove {
+				length -= op.End - op.Start
+			}
+		}
+		ops[i] = op
+	}
+	return ops
+}
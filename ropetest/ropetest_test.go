@@ -0,0 +1,41 @@
+package ropetest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+func randomInt(random *rand.Rand) int {
+	return random.Intn(1000)
+}
+
+func TestCheckOperations(t *testing.T) {
+	allSettings := map[string]*rope.Settings{
+		"default": rope.DefaultSettings,
+		"small":   {SplitLength: 4, JoinLength: 2, Rebalance: 1.5},
+		"gap":     {SplitLength: 8, JoinLength: 4, Rebalance: 1.5, GapBuffer: true},
+		"lazy":    {SplitLength: 8, JoinLength: 4, Rebalance: 1.5, LazyRemove: true},
+		"arena":   {SplitLength: 8, JoinLength: 4, Rebalance: 1.5, Arena: rope.NewArena(16)},
+	}
+	for name, settings := range allSettings {
+		t.Run(name, func(t *testing.T) {
+			random := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				CheckOperations(t, RandomOps(random, 200, 20, randomInt), settings)
+			}
+		})
+	}
+}
+
+func FuzzOperations(f *testing.F) {
+	f.Add(int64(0), 4)
+	f.Fuzz(func(t *testing.T, seed int64, splitLength int) {
+		if splitLength < 2 || splitLength > 1000 {
+			t.Skip()
+		}
+		settings := &rope.Settings{SplitLength: splitLength, JoinLength: splitLength / 2, Rebalance: 1.5}
+		CheckOperations(t, RandomOps(rand.New(rand.NewSource(seed)), 100, 10, randomInt), settings)
+	})
+}
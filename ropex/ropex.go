@@ -0,0 +1,89 @@
+// Package ropex has the functions on ropes that need comparable or
+// ordered elements, which methods of the generic rope can't require.
+package ropex
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+// Whether both have the same values, comparing leaf by leaf
+func Equal[T comparable](a, b *rope.Rope[T]) bool {
+	if a.SameAs(b) {
+		return true
+	}
+	if a.Length() != b.Length() {
+		return false
+	}
+	return CompareFunc(a, b, func(x, y T) int {
+		if x == y {
+			return 0
+		}
+		return 1
+	}) == 0
+}
+
+// Returns the index of the first occurrence of the value, or -1
+func Index[T comparable](r *rope.Rope[T], value T) int {
+	for index, element := range r.All(0, r.Length()) {
+		if element == value {
+			return index
+		}
+	}
+	return -1
+}
+
+func Contains[T comparable](r *rope.Rope[T], value T) bool {
+	return Index(r, value) >= 0
+}
+
+// Returns the smallest value, panicking if the rope is empty, like slices.Min
+func Min[T cmp.Ordered](r *rope.Rope[T]) T {
+	return extreme(r, func(a, b T) bool { return a < b })
+}
+
+// Returns the largest value, panicking if the rope is empty, like slices.Max
+func Max[T cmp.Ordered](r *rope.Rope[T]) T {
+	return extreme(r, func(a, b T) bool { return a > b })
+}
+
+func extreme[T cmp.Ordered](r *rope.Rope[T], better func(a, b T) bool) T {
+	if r.IsEmpty() {
+		panic("ropex: empty rope")
+	}
+	best := r.At(0)
+	for chunk := range r.Chunks(0, r.Length()) {
+		for _, value := range chunk {
+			if better(value, best) {
+				best = value
+			}
+		}
+	}
+	return best
+}
+
+// Compares the values lexicographically, like slices.Compare
+func Compare[T cmp.Ordered](a, b *rope.Rope[T]) int {
+	return CompareFunc(a, b, cmp.Compare[T])
+}
+
+// Compares the values lexicographically with compare, like slices.CompareFunc
+func CompareFunc[T any](a, b *rope.Rope[T], compare func(x, y T) int) int {
+	next, stop := iter.Pull2(b.All(0, b.Length()))
+	defer stop()
+	for _, x := range a.All(0, a.Length()) {
+		_, y, ok := next()
+		if !ok {
+			return 1
+		}
+		if result := compare(x, y); result != 0 {
+			return result
+		}
+	}
+	if _, _, ok := next(); ok {
+		return -1
+	}
+	return 0
+}
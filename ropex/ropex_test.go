@@ -0,0 +1,62 @@
+package ropex
+
+import (
+	"testing"
+
+	"github.com/hhhhhhhhhn/rope"
+)
+
+var settings = &rope.Settings{SplitLength: 4, JoinLength: 2, Rebalance: 1.5}
+
+func TestEqual(t *testing.T) {
+	a := rope.NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, settings)
+	b := rope.NewRope([]int{0, 1, 2}, settings).Append([]int{3, 4, 5, 6, 7})
+	if !Equal(a, a) || !Equal(a, b) {
+		t.Error("Equal ropes not equal")
+	}
+	if Equal(a, b.Replace(7, []int{-1})) || Equal(a, b.Remove(0, 1)) {
+		t.Error("Different ropes equal")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	r := rope.NewRope([]int{5, 3, 9, 3, 7, 1, 8, 2}, settings)
+	if Index(r, 3) != 1 || Index(r, 2) != 7 || Index(r, 4) != -1 {
+		t.Error("Wrong index:", Index(r, 3), Index(r, 2), Index(r, 4))
+	}
+	if !Contains(r, 8) || Contains(r, 0) {
+		t.Error("Wrong contains")
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	r := rope.NewRope([]int{5, 3, 9, 3, 7, 1, 8, 2}, settings)
+	if Min(r) != 1 || Max(r) != 9 {
+		t.Error("Wrong extremes:", Min(r), Max(r))
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Min of an empty rope didn't panic")
+		}
+	}()
+	Min(rope.Empty[int](settings))
+}
+
+func TestCompare(t *testing.T) {
+	a := rope.NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, settings)
+	cases := []struct {
+		other    []int
+		expected int
+	}{
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, 0},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 8}, -1},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 6}, 1},
+		{[]int{0, 1, 2}, 1},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7, 8}, -1},
+	}
+	for _, c := range cases {
+		if result := Compare(a, rope.NewRope(c.other, settings)); result != c.expected {
+			t.Error("Wrong comparison with", c.other, "got", result)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package rope
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+type SaveOptions struct {
+	Perm          os.FileMode // Of new files, 0644 if unset. Existing files keep theirs.
+	SkipUnchanged bool        // Whether to skip saving if the content is the one last saved to the path
+}
+
+// What a document was last saved as
+type savedFile struct {
+	path        string
+	fingerprint [16]byte
+}
+
+// Saves the current version to the file, writing it to a temporary file
+// in the same directory, syncing it, and renaming it over the file, so
+// the file always has the whole of either version, even after a crash.
+// Byte documents are written as they are, and others with WriteChunks.
+// With SkipUnchanged, nothing is written if the Fingerprint, cached on
+// the nodes, is the one from the last save to the path.
+func (d *Document[T]) SaveTo(path string, options SaveOptions) error {
+	rope := d.Rope()
+	saved := savedFile{path, rope.Fingerprint()}
+	if options.SkipUnchanged && saved == d.saved {
+		return nil
+	}
+	perm := options.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "." + filepath.Base(path) + ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // Fails once renamed
+	if err := writeSaved(file, rope, perm); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	if err := syncDir(dir); err != nil { // So the rename is durable too
+		return err
+	}
+	d.saved = saved
+	return nil
+}
+
+func writeSaved[T any](file *os.File, rope *Rope[T], perm os.FileMode) error {
+	if bytesRope, ok := any(rope).(*Rope[byte]); ok {
+		if _, err := NewReader(bytesRope).WriteTo(file); err != nil {
+			return err
+		}
+	} else if err := rope.WriteChunks(file); err != nil {
+		return err
+	}
+	if err := file.Chmod(perm); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func syncDir(dir string) error {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	if err := handle.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package rope
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveTo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "document.txt")
+	document := NewDocument(NewRope([]byte("first version"), testSettings))
+	err := document.SaveTo(path, SaveOptions{SkipUnchanged: true})
+	content, _ := os.ReadFile(path)
+	assert(t, err == nil && string(content) == "first version", "Wrong content saved:", string(content), err)
+	os.Chmod(path, 0o600)
+
+	os.WriteFile(path, []byte("changed outside"), 0o600)
+	err = document.SaveTo(path, SaveOptions{SkipUnchanged: true})
+	content, _ = os.ReadFile(path)
+	assert(t, err == nil && string(content) == "changed outside", "Unchanged document saved again")
+
+	document.Apply(Edit[byte]{0, 5, []byte("second")})
+	err = document.SaveTo(path, SaveOptions{SkipUnchanged: true})
+	content, _ = os.ReadFile(path)
+	assert(t, err == nil && string(content) == "second version", "Wrong content saved:", string(content), err)
+	info, _ := os.Stat(path)
+	assert(t, info.Mode().Perm() == 0o600, "Permissions not kept:", info.Mode())
+	entries, _ := os.ReadDir(dir)
+	assert(t, len(entries) == 1, "Temporary files left:", entries)
+
+	err = NewDocument(NewRope([]byte{}, testSettings)).SaveTo(filepath.Join(dir, "missing", "file"), SaveOptions{})
+	assert(t, err != nil, "Saved in a missing directory")
+}
+
+func TestSaveToChunks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "numbers")
+	document := NewDocument(NewRope([]int32{1, 2, 3, 4, 5, 6}, testSettings))
+	assert(t, document.SaveTo(path, SaveOptions{Perm: 0o640}) == nil, "Couldn't save")
+	file, _ := os.Open(path)
+	defer file.Close()
+	read, err := ReadChunks[int32](file, testSettings)
+	assert(t, err == nil, "Couldn't read:", err)
+	assertSameValue(t, read, document.Rope())
+	info, _ := os.Stat(path)
+	assert(t, info.Mode().Perm() == 0o640, "Wrong permissions:", info.Mode())
+}
@@ -0,0 +1,24 @@
+package rope
+
+// Returns a rope with the same content obeying the new settings. Leaves
+// are reused without copying where they fit the new thresholds, split if
+// now too long, and joined with their sibling if now too short.
+func (r *Rope[T]) WithSettings(settings *Settings) *Rope[T] {
+	if settings == nil {
+		panic(ErrNilSettings)
+	}
+	if r.value != nil {
+		r.disown()
+		// Capacities are clamped, as the spare capacity belongs to the claim
+		if r.piece {
+			return newPiece(r.value[:r.length:r.length], settings)
+		}
+		return NewRope(r.value[:r.length:r.length], settings)
+	}
+	changed := newNode[T](settings)
+	changed.length = r.length
+	changed.left = r.left.WithSettings(settings)
+	changed.right = r.right.WithSettings(settings)
+	changed.adjust()
+	return changed
+}
@@ -0,0 +1,28 @@
+package rope
+
+import "testing"
+
+func TestWithSettings(t *testing.T) {
+	value := make([]int, 100)
+	for i := range value {
+		value[i] = i
+	}
+	loading := &Settings{SplitLength: 64, JoinLength: 32, Rebalance: 1.5}
+	editing := &Settings{SplitLength: 8, JoinLength: 4, Rebalance: 1.5}
+	rope := NewRope(value, loading)
+
+	changed := rope.WithSettings(editing)
+	assertValue(t, changed, value)
+	assert(t, changed.Validate() == nil, "Invalid rope:", changed.Validate())
+	assert(t, changed.settings == editing, "Settings not changed")
+	assert(t, rope.settings == loading && rope.Validate() == nil, "Original changed")
+
+	back := changed.WithSettings(loading)
+	assertValue(t, back, value)
+	assert(t, back.Validate() == nil, "Invalid rope:", back.Validate())
+
+	piece := NewPieceRope(value, loading).WithSettings(editing)
+	assert(t, piece.piece, "Piece not kept")
+	inserted := piece.Insert(50, []int{-1})
+	assert(t, inserted.At(50) == -1 && inserted.At(51) == 50, "Wrong insertion:", inserted.Slice(49, 52))
+}
@@ -0,0 +1,220 @@
+package rope
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A snapshot file starts with the magic and format version, followed by
+// the nodes, children before parents, and then the ids of the roots:
+//
+//	magic, version byte
+//	uvarint node count
+//	  leaf:  0, uvarint size, encoded elements
+//	  split: 1, uvarint left id, uvarint right id
+//	uvarint root count, uvarint root ids
+const (
+	snapshotMagic   = "ROPS"
+	snapshotVersion = 1
+	snapshotLeaf    = 0
+	snapshotSplit   = 1
+)
+
+type snapshotKey struct {
+	fingerprint fingerprint
+	length      int
+}
+
+// Writes many versions of a rope into one snapshot, with each subtree
+// written once, even when the versions built it separately, so a
+// session with its full undo history isn't multiplied in size.
+func WriteSnapshot[T any](w io.Writer, ropes ...*Rope[T]) error {
+	snapshot := &snapshotWriter[T]{
+		ids:     map[*Rope[T]]int{},
+		content: map[snapshotKey]int{},
+	}
+	roots := make([]int, len(ropes))
+	for i, rope := range ropes {
+		root, err := snapshot.add(rope)
+		if err != nil {
+			return err
+		}
+		roots[i] = root
+	}
+
+	buffered := bufio.NewWriter(w)
+	buffered.WriteString(snapshotMagic)
+	buffered.WriteByte(snapshotVersion)
+	writeUvarint(buffered, len(snapshot.nodes))
+	for _, node := range snapshot.nodes {
+		buffered.Write(node)
+	}
+	writeUvarint(buffered, len(roots))
+	for _, root := range roots {
+		writeUvarint(buffered, root)
+	}
+	return buffered.Flush()
+}
+
+type snapshotWriter[T any] struct {
+	ids     map[*Rope[T]]int       // Already written nodes
+	content map[snapshotKey]int    // Already written content
+	nodes   [][]byte               // Encoded nodes, by id
+}
+
+func (s *snapshotWriter[T]) add(r *Rope[T]) (int, error) {
+	if id, ok := s.ids[r]; ok {
+		return id, nil
+	}
+	key := snapshotKey{r.fingerprint(), r.length}
+	if id, ok := s.content[key]; ok {
+		s.ids[r] = id
+		return id, nil
+	}
+
+	var node []byte
+	if r.value != nil {
+		encoded, err := encodeElements(r.value)
+		if err != nil {
+			return 0, err
+		}
+		node = append([]byte{snapshotLeaf}, binary.AppendUvarint(nil, uint64(len(encoded)))...)
+		node = append(node, encoded...)
+	} else {
+		left, err := s.add(r.left)
+		if err != nil {
+			return 0, err
+		}
+		right, err := s.add(r.right)
+		if err != nil {
+			return 0, err
+		}
+		node = binary.AppendUvarint([]byte{snapshotSplit}, uint64(left))
+		node = binary.AppendUvarint(node, uint64(right))
+	}
+	id := len(s.nodes)
+	s.nodes = append(s.nodes, node)
+	s.ids[r] = id
+	s.content[key] = id
+	return id, nil
+}
+
+// Reads the ropes written by WriteSnapshot, in the same order, sharing
+// the nodes that were shared when written
+func ReadSnapshot[T any](r io.Reader, settings *Settings) ([]*Rope[T], error) {
+	buffered := bufio.NewReader(r)
+	header := make([]byte, len(snapshotMagic) + 1)
+	if _, err := io.ReadFull(buffered, header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic || header[len(snapshotMagic)] != snapshotVersion {
+		return nil, fmt.Errorf("%w: not a snapshot", ErrInvalidEncoding)
+	}
+
+	count, err := readUvarint(buffered)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []*Rope[T]{}
+	for i := 0; i < count; i++ {
+		node, err := readSnapshotNode(buffered, nodes, settings)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	count, err = readUvarint(buffered)
+	if err != nil {
+		return nil, err
+	}
+	ropes := []*Rope[T]{}
+	for i := 0; i < count; i++ {
+		root, err := readId(buffered, len(nodes))
+		if err != nil {
+			return nil, err
+		}
+		ropes = append(ropes, nodes[root])
+	}
+	return ropes, nil
+}
+
+func readSnapshotNode[T any](r *bufio.Reader, nodes []*Rope[T], settings *Settings) (*Rope[T], error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case snapshotLeaf:
+		size, err := readUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := readSized(r, size)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeElements[T](encoded)
+		if err != nil {
+			return nil, err
+		}
+		return NewRope(values, settings), nil
+	case snapshotSplit:
+		left, err := readId(r, len(nodes))
+		if err != nil {
+			return nil, err
+		}
+		right, err := readId(r, len(nodes))
+		if err != nil {
+			return nil, err
+		}
+		node := newNode[T](settings)
+		node.left, node.right = nodes[left], nodes[right]
+		node.length = node.left.length + node.right.length
+		node.adjust()
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: unknown node kind %d", ErrInvalidEncoding, kind)
+}
+
+func writeUvarint(w io.Writer, value int) error {
+	var buffer [binary.MaxVarintLen64]byte
+	_, err := w.Write(buffer[:binary.PutUvarint(buffer[:], uint64(value))])
+	return err
+}
+
+func readUvarint(r io.ByteReader) (int, error) {
+	value, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if value > uint64(^uint(0) >> 1) {
+		return 0, fmt.Errorf("%w: number too large", ErrInvalidEncoding)
+	}
+	return int(value), err
+}
+
+// Reads size bytes, growing the buffer as they arrive, so a corrupted
+// size fails with ErrInvalidEncoding instead of allocating all of it
+func readSized(r io.Reader, size int) ([]byte, error) {
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r, int64(size)); err == io.EOF {
+		return nil, fmt.Errorf("%w: %d bytes expected, %d left: %w",
+			ErrInvalidEncoding, size, buffer.Len(), io.ErrUnexpectedEOF)
+	} else if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Reads the id of an already read node
+func readId(r io.ByteReader, count int) (int, error) {
+	id, err := readUvarint(r)
+	if err == nil && id >= count {
+		err = fmt.Errorf("%w: reference to node %d of %d", ErrInvalidEncoding, id, count)
+	}
+	return id, err
+}
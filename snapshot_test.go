@@ -0,0 +1,41 @@
+package rope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	originalValue := make([]int, 10000)
+	for i := range originalValue {
+		originalValue[i] = i
+	}
+	versions := []*Rope[int]{NewRope(originalValue, DefaultSettings)}
+	for i := 0; i < 20; i++ {
+		versions = append(versions, versions[i].Insert(i * 300, []int{-i}))
+	}
+	versions = append(versions, NewRope(originalValue, DefaultSettings)) // Built separately
+
+	var single, snapshot bytes.Buffer
+	assert(t, WriteSnapshot(&single, versions[0]) == nil, "Couldn't write snapshot")
+	assert(t, WriteSnapshot(&snapshot, versions...) == nil, "Couldn't write snapshot")
+	assert(t, snapshot.Len() < 2 * single.Len(), "Versions not shared:", snapshot.Len(), single.Len())
+
+	read, err := ReadSnapshot[int](&snapshot, DefaultSettings)
+	assert(t, err == nil, "Unexpected error:", err)
+	assert(t, len(read) == len(versions), "Wrong number of versions:", len(read))
+	for i := range versions {
+		assertSameValue(t, versions[i], read[i])
+		assert(t, read[i].Validate() == nil, "Invalid rope:", read[i].Validate())
+	}
+	assert(t, read[0] == read[len(read) - 1], "Equal content not shared")
+	assert(t, ShareStats(read[0], read[20]).Nodes > 0, "Nodes not shared")
+
+	_, err = ReadSnapshot[int](bytes.NewReader([]byte("ROPS\x01\x01\x01\x00\x00")), DefaultSettings)
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Wrong error:", err)
+	corrupted := binary.AppendUvarint([]byte("ROPS\x01\x01\x00"), 1 << 50)
+	_, err = ReadSnapshot[int](bytes.NewReader(corrupted), DefaultSettings)
+	assert(t, errors.Is(err, ErrInvalidEncoding), "Corrupted size not rejected:", err)
+}
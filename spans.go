@@ -0,0 +1,122 @@
+package rope
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+type Span[V any] struct {
+	Range
+	Value V
+}
+
+// Keeps values, like the tokens of a syntax highlighter, on ranges of a
+// rope which don't overlap, moving them with the edits done to it, and
+// reporting the ones each edit invalidates, so only they must be
+// computed again. The offsets of the spans after the last edit are
+// moved lazily, as in a gap buffer, so edits close together don't move
+// all of them. The zero value is empty. Unlike ropes, spans aren't safe
+// for concurrent use.
+type Spans[V any] struct {
+	spans []Span[V]
+	moved int // Spans from here are shifted by shift
+	shift int
+}
+
+// Returns the span with its offsets shifted if needed
+func (s *Spans[V]) at(i int) Span[V] {
+	span := s.spans[i]
+	if i >= s.moved {
+		span.Start += s.shift
+		span.End += s.shift
+	}
+	return span
+}
+
+// Applies the shift to the spans until i, or removes it from the ones
+// after it, so it applies from i
+func (s *Spans[V]) moveTo(i int) {
+	for ; s.moved < i; s.moved++ {
+		s.spans[s.moved].Start += s.shift
+		s.spans[s.moved].End += s.shift
+	}
+	for ; s.moved > i; s.moved-- {
+		s.spans[s.moved - 1].Start -= s.shift
+		s.spans[s.moved - 1].End -= s.shift
+	}
+}
+
+// Returns the index of the first span ending after the offset, or at it
+// if touching is set
+func (s *Spans[V]) firstEnding(offset int, touching bool) int {
+	return sort.Search(len(s.spans), func(i int) bool {
+		end := s.at(i).End
+		return end > offset || (touching && end == offset)
+	})
+}
+
+// Returns the index of the first span starting after the offset, or at
+// it if touching is not set
+func (s *Spans[V]) firstStarting(offset int, touching bool) int {
+	return sort.Search(len(s.spans), func(i int) bool {
+		start := s.at(i).Start
+		return start > offset || (!touching && start == offset)
+	})
+}
+
+func (s *Spans[V]) Len() int {
+	return len(s.spans)
+}
+
+// Replaces the spans overlapping the range with spans, which must be sorted,
+// not overlap, and be inside it, like the tokens of the range lexed again
+func (s *Spans[V]) Replace(start, end int, spans []Span[V]) {
+	first, last := s.firstEnding(start, false), s.firstStarting(end, false)
+	s.moveTo(last)
+	s.spans = slices.Replace(s.spans, first, last, spans...)
+	s.moved = first + len(spans)
+}
+
+// Records that the range was replaced by length elements: removes the spans
+// overlapping or touching it, which are returned, and moves the ones after
+// it. damaged is the range, after the edit, to compute the spans of again,
+// from the start of the first removed to the end of the last.
+func (s *Spans[V]) Edit(start, end, length int) (damaged Range, invalidated []Span[V]) {
+	delta := length - (end - start)
+	first, last := s.firstEnding(start, true), s.firstStarting(end, true)
+	damaged = Range{start, start + length}
+	for i := first; i < last; i++ {
+		invalidated = append(invalidated, s.at(i))
+	}
+	if len(invalidated) > 0 {
+		damaged.Start = min(start, invalidated[0].Start)
+		damaged.End = max(end, invalidated[len(invalidated) - 1].End) + delta
+	}
+	s.moveTo(last)
+	s.shift += delta
+	s.spans = slices.Delete(s.spans, first, last)
+	s.moved = first
+	return damaged, invalidated
+}
+
+// Returns the span with the offset, if there is one
+func (s *Spans[V]) At(offset int) (Span[V], bool) {
+	i := s.firstEnding(offset, false)
+	if i < len(s.spans) && s.at(i).Start <= offset {
+		return s.at(i), true
+	}
+	return Span[V]{}, false
+}
+
+// Yields the spans overlapping the range, in order
+func (s *Spans[V]) Overlapping(start, end int) iter.Seq[Span[V]] {
+	return func(yield func(Span[V]) bool) {
+		for i := s.firstEnding(start, false); i < len(s.spans); i++ {
+			span := s.at(i)
+			if span.Start >= end || !yield(span) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+func span(start, end int, value string) Span[string] {
+	return Span[string]{Range{start, end}, value}
+}
+
+func TestSpansEdit(t *testing.T) {
+	// As in "let x = 10;"
+	spans := &Spans[string]{}
+	tokens := []Span[string]{span(0, 3, "keyword"), span(4, 5, "name"), span(6, 7, "operator"), span(8, 10, "number"), span(10, 11, "end")}
+	spans.Replace(0, 11, tokens)
+	assert(t, spans.Len() == 5, "Wrong length")
+
+	damaged, invalidated := spans.Edit(5, 5, 2) // "let xyz = 10;"
+	assert(t, damaged == Range{4, 7}, "Wrong damaged range", damaged)
+	assert(t, slices.Equal(invalidated, tokens[1:2]), "Wrong invalidated spans", invalidated)
+	spans.Replace(damaged.Start, damaged.End, []Span[string]{span(4, 7, "name")})
+	assert(t, slices.Equal(slices.Collect(spans.Overlapping(0, 13)),
+		[]Span[string]{span(0, 3, "keyword"), span(4, 7, "name"), span(8, 9, "operator"), span(10, 12, "number"), span(12, 13, "end")}),
+		"Wrong spans after the edit", slices.Collect(spans.Overlapping(0, 13)))
+
+	damaged, invalidated = spans.Edit(10, 12, 1) // "let xyz = 5;"
+	assert(t, damaged == Range{10, 12} && len(invalidated) == 2, "Wrong damaged range", damaged, invalidated)
+	assert(t, spans.Len() == 3, "Invalidated spans not removed")
+	damaged, invalidated = spans.Edit(0, 0, 1) // Before everything
+	assert(t, damaged == Range{0, 4} && len(invalidated) == 1, "Wrong damaged range at the start", damaged)
+	found, ok := spans.At(6)
+	assert(t, ok && found.Start == 5 && found.End == 8, "Wrong span at offset", found)
+	_, ok = spans.At(4)
+	assert(t, !ok, "Span found in a gap")
+}
+
+func TestSpansMoveLazily(t *testing.T) {
+	spans := &Spans[int]{}
+	all := []Span[int]{}
+	for i := range 100 {
+		all = append(all, Span[int]{Range{2 * i, 2 * i + 1}, i})
+	}
+	spans.Replace(0, 200, all)
+	for range 10 {
+		spans.Edit(199, 199, 3) // Typing at the end
+	}
+	assert(t, spans.moved == 99 && spans.Len() == 99, "Spans moved eagerly", spans.moved)
+	spans.Edit(101, 101, 2) // Touching the span of 50
+	assert(t, spans.moved == 50 && spans.Len() == 98, "Wrong spans moved", spans.moved)
+	span, ok := spans.At(10)
+	assert(t, ok && span.Value == 5, "Span before the edits moved", span)
+	span, ok = spans.At(182)
+	assert(t, ok && span.Value == 90 && span.End == 183, "Wrong span after the edits", span)
+}
@@ -0,0 +1,18 @@
+package rope
+
+// A SplitAt for byte ropes, which ends the left side just after the newline
+// closest to the middle, so whole lines mostly stay inside single leaves.
+// Only the middle half is searched, to keep the leaves evenly sized,
+// and the midpoint is used if there is no newline there.
+func SplitAtNewline(leaf []byte) int {
+	middle := len(leaf) / 2
+	for distance := 0; distance <= len(leaf) / 4; distance++ {
+		if after := middle + distance; after < len(leaf) && leaf[after - 1] == '\n' {
+			return after
+		}
+		if before := middle - distance; before > 0 && leaf[before - 1] == '\n' {
+			return before
+		}
+	}
+	return middle
+}
@@ -0,0 +1,39 @@
+package rope
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitAtNewline(t *testing.T) {
+	assert(t, SplitAtNewline([]byte("abc\ndefghijk")) == 4, "Newline not used")
+	assert(t, SplitAtNewline([]byte("abcdefg\nhijk")) == 8, "Newline not used")
+	assert(t, SplitAtNewline([]byte("a\nbcdefghijk")) == 6, "Newline too far used")
+	assert(t, SplitAtNewline([]byte("abcdefghijkl")) == 6, "Midpoint not used")
+
+	settings := *DefaultSettings
+	settings.SplitLength, settings.JoinLength = 64, 32
+	settings.SplitAt = SplitAtNewline
+	text := bytes.Repeat([]byte("a line of text\n"), 100)
+	endsInNewlines := func(rope *Rope[byte]) {
+		rope.walk(0, rope.length, func(leaf []byte) bool {
+			assert(t, leaf[len(leaf) - 1] == '\n', "Leaf not ending in newline:", string(leaf))
+			return true
+		})
+	}
+	rope := NewRope(text, &settings)
+	assertValue(t, rope, text)
+	endsInNewlines(rope)
+
+	inserted := NewRope([]byte("first\n"), &settings).Insert(6, text)
+	assertValue(t, inserted, append([]byte("first\n"), text...))
+	endsInNewlines(inserted)
+	unmarshaled := Empty[byte](&settings)
+	data, _ := rope.MarshalBinary()
+	assert(t, unmarshaled.UnmarshalBinary(data) == nil, "Couldn't unmarshal")
+	endsInNewlines(unmarshaled)
+	settings.MaxDepth = 4
+	limited := NewRope(text[:60], &settings).Insert(60, text[60:])
+	assert(t, limited.Depth() <= 4 || limited.Depth() == limited.IdealDepth(), "Depth not limited:", limited.Depth())
+	endsInNewlines(limited)
+}
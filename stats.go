@@ -0,0 +1,135 @@
+package rope
+
+import "unsafe"
+
+type Stats struct {
+	Nodes   int     // Both leaves and split nodes
+	Leaves  int
+	Depth   int     // A single leaf has a depth of 1
+	MinLeaf int     // Length of the shortest leaf
+	MaxLeaf int     // Length of the longest leaf
+	AvgLeaf float64 // Average length of the leaves
+	Bytes   int     // Estimated memory used by the nodes and leaves
+}
+
+// Collects statistics about the structure of the rope, to help tune
+// SplitLength and JoinLength with data.
+func (r *Rope[T]) Stats() Stats {
+	stats := Stats{MinLeaf: r.length}
+	r.collectStats(&stats, 1)
+	stats.AvgLeaf = float64(r.length) / float64(stats.Leaves)
+	return stats
+}
+
+func (r *Rope[T]) collectStats(stats *Stats, depth int) {
+	var element T
+	stats.Nodes++
+	stats.Bytes += int(unsafe.Sizeof(*r))
+	if depth > stats.Depth {
+		stats.Depth = depth
+	}
+	if r.value != nil {
+		stats.Leaves++
+		stats.Bytes += cap(r.value) * int(unsafe.Sizeof(element))
+		if r.length < stats.MinLeaf {
+			stats.MinLeaf = r.length
+		}
+		if r.length > stats.MaxLeaf {
+			stats.MaxLeaf = r.length
+		}
+		return
+	}
+	r.left.collectStats(stats, depth + 1)
+	r.right.collectStats(stats, depth + 1)
+}
+
+type Sharing struct {
+	Nodes int // Nodes present in both ropes
+	Bytes int // Estimated memory used by the shared nodes and their leaves
+}
+
+// Measures how much structure two ropes share, for example to estimate
+// the memory cost of keeping both versions.
+func ShareStats[T any](a, b *Rope[T]) Sharing {
+	nodes := map[*Rope[T]]bool{}
+	a.collectNodes(nodes)
+	sharing := Sharing{}
+	b.collectSharing(nodes, &sharing)
+	return sharing
+}
+
+func (r *Rope[T]) collectNodes(nodes map[*Rope[T]]bool) {
+	nodes[r] = true
+	if r.value == nil {
+		r.left.collectNodes(nodes)
+		r.right.collectNodes(nodes)
+	}
+}
+
+func (r *Rope[T]) collectSharing(nodes map[*Rope[T]]bool, sharing *Sharing) {
+	if nodes[r] { // The whole subtree is shared
+		stats := r.Stats()
+		sharing.Nodes += stats.Nodes
+		sharing.Bytes += stats.Bytes
+		return
+	}
+	if r.value == nil {
+		r.left.collectSharing(nodes, sharing)
+		r.right.collectSharing(nodes, sharing)
+	}
+}
+
+// Whether both are the same version, which is O(1), as a rope is never
+// modified in place by edits. Ropes with equal values may still differ.
+func (r *Rope[T]) SameAs(other *Rope[T]) bool {
+	return r == other
+}
+
+// Returns the fraction of the leaves of r that other also has,
+// from 0 for unrelated ropes to 1 for the same version.
+func (r *Rope[T]) SharedWith(other *Rope[T]) float64 {
+	if r == other {
+		return 1
+	}
+	nodes := map[*Rope[T]]bool{}
+	other.collectNodes(nodes)
+	shared, leaves := r.countSharedLeaves(nodes, false)
+	return float64(shared) / float64(leaves)
+}
+
+func (r *Rope[T]) countSharedLeaves(nodes map[*Rope[T]]bool, shared bool) (sharedLeaves, leaves int) {
+	shared = shared || nodes[r]
+	if r.value != nil {
+		if shared {
+			return 1, 1
+		}
+		return 0, 1
+	}
+	leftShared, leftLeaves := r.left.countSharedLeaves(nodes, shared)
+	rightShared, rightLeaves := r.right.countSharedLeaves(nodes, shared)
+	return leftShared + rightShared, leftLeaves + rightLeaves
+}
+
+type Fragmentation struct {
+	Leaves      int
+	ShortLeaves int     // Shorter than JoinLength
+	IdealLeaves int     // How many leaves of SplitLength would be needed
+	Ratio       float64 // Leaves per ideal leaf, 1 when not fragmented
+	SpareBytes  int     // Estimated memory in the backing arrays outside the leaves
+}
+
+// Measures how far the leaves are from filling SplitLength, and how much
+// memory they retain, to decide when Compact is worth it
+func (r *Rope[T]) FragmentationStats() Fragmentation {
+	var element T
+	fragmentation := Fragmentation{IdealLeaves: max(1, (r.length + r.settings.SplitLength - 1) / r.settings.SplitLength)}
+	r.walkLeaves(func(leaf *Rope[T]) {
+		fragmentation.Leaves++
+		if leaf.length < r.settings.JoinLength {
+			fragmentation.ShortLeaves++
+		}
+		fragmentation.SpareBytes += (cap(leaf.value) - leaf.length) * int(unsafe.Sizeof(element))
+	})
+	fragmentation.Ratio = float64(fragmentation.Leaves) / float64(fragmentation.IdealLeaves)
+	return fragmentation
+}
@@ -0,0 +1,48 @@
+package rope
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings).Insert(1, []int{-1})
+	stats := rope.Stats()
+
+	assert(t, stats.Leaves == 3, "Wrong leaf count:", stats.Leaves)
+	assert(t, stats.Nodes == 5, "Wrong node count:", stats.Nodes)
+	assert(t, stats.Depth == maxDepth(rope), "Wrong depth:", stats.Depth)
+	assert(t, stats.MinLeaf == 2 && stats.MaxLeaf == 4, "Wrong leaf lengths:", stats.MinLeaf, stats.MaxLeaf)
+	assert(t, stats.AvgLeaf == 3, "Wrong average leaf length:", stats.AvgLeaf)
+	assert(t, stats.Bytes > 9 * 8, "Memory estimate too low:", stats.Bytes)
+}
+
+func TestShareStats(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	newRope := rope.Insert(1, []int{-1})
+
+	assert(t, ShareStats(rope, rope).Nodes == 3, "A rope must share all nodes with itself")
+	assert(t, ShareStats(rope, newRope).Nodes == 1, "Only the right leaf should be shared")
+	assert(t, ShareStats(rope, newRope).Bytes == newRope.right.Stats().Bytes, "Wrong shared bytes")
+	assert(t, ShareStats(rope, NewRope(rope.Value(), testSettings)).Nodes == 0, "Unrelated ropes can't share")
+}
+
+func TestSharedWith(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings) // Two leaves
+	changed := rope.Replace(0, []int{-1})
+
+	assert(t, rope.SameAs(rope) && !rope.SameAs(changed), "Wrong identity")
+	assert(t, !rope.SameAs(NewRope(rope.Value(), testSettings)), "Equal ropes are not the same")
+	assert(t, rope.SharedWith(rope) == 1, "Not fully shared:", rope.SharedWith(rope))
+	assert(t, changed.SharedWith(rope) == 0.5, "Wrong fraction:", changed.SharedWith(rope))
+	assert(t, rope.SharedWith(NewRope(rope.Value(), testSettings)) == 0, "Unrelated ropes share")
+}
+
+func TestFragmentationStats(t *testing.T) {
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings).Insert(1, []int{-1})
+	fragmentation := rope.FragmentationStats()
+
+	assert(t, fragmentation.Leaves == 3 && fragmentation.IdealLeaves == 3, "Wrong leaf counts:", fragmentation)
+	assert(t, fragmentation.ShortLeaves == 0 && fragmentation.Ratio == 1, "Wrong fragmentation:", fragmentation)
+	assert(t, fragmentation.SpareBytes == 0, "Wrong spare memory:", fragmentation)
+
+	spare := NewRope(make([]int, 1, 5), testSettings).FragmentationStats()
+	assert(t, spare.SpareBytes == 4 * 8 && spare.ShortLeaves == 1, "Wrong spare memory:", spare)
+}
@@ -0,0 +1,117 @@
+package rope
+
+import (
+	"fmt"
+	"math"
+)
+
+// Returns the range as a rope of its own, sharing the nodes inside it
+// and referencing the leaves at its ends, so only O(log n) nodes are
+// created, and nothing is copied except short leaves being joined.
+func (r *Rope[T]) SubRope(start, end int) *Rope[T] {
+	start, end = r.mustRange(start, end)
+	return r.subRope(start, end)
+}
+
+func (r *Rope[T]) subRope(start, end int) *Rope[T] {
+	if start == 0 && end == r.length {
+		return r
+	}
+	if start == end {
+		return NewRope([]T{}, r.settings)
+	}
+	if r.value != nil { // Isn't split
+		r.disown()
+		if r.piece {
+			return newPiece(r.value[start:end:end], r.settings)
+		}
+		return NewRope(r.value[start:end:end], r.settings)
+	}
+	// Is split
+	if end <= r.left.length {
+		return r.left.subRope(start, end)
+	}
+	if start >= r.left.length {
+		return r.right.subRope(start - r.left.length, end - r.left.length)
+	}
+	return link(r.left.subRope(start, r.left.length), r.right.subRope(0, end - r.left.length))
+}
+
+// Splits the rope around the elements matching the predicate, which are
+// left out, like strings.Split with them as separators, so there is one
+// more part than matches. The parts are sub-ropes, sharing the nodes.
+func (r *Rope[T]) SplitFunc(separator func(T) bool) []*Rope[T] {
+	parts := []*Rope[T]{}
+	start := 0
+	for index, value := range r.All(0, r.length) {
+		if separator(value) {
+			parts = append(parts, r.subRope(start, index))
+			start = index + 1
+		}
+	}
+	return append(parts, r.subRope(start, r.length))
+}
+
+// Splits the rope into n sub-ropes of about the same length, one after
+// the other, cutting only between leaves, so they share every node
+// inside them and nothing is copied. They can then be processed
+// concurrently, and the results joined back with Concat. Parts are
+// empty if there are fewer leaves than n, which is at least 1.
+func (r *Rope[T]) SplitN(n int) []*Rope[T] {
+	n = max(n, 1)
+	parts := make([]*Rope[T], 0, n)
+	start := 0
+	for i := 1; i <= n; i++ {
+		end := r.length
+		if i < n && r.length > 0 {
+			target := r.length / n * i + r.length % n * i / n // Not overflowing
+			end = max(start, r.leafBoundary(target))
+		}
+		parts = append(parts, r.subRope(start, end))
+		start = end
+	}
+	return parts
+}
+
+// Returns the start or end of the leaf with the index, whichever is closer
+func (r *Rope[T]) leafBoundary(index int) int {
+	leaf, leafStart := r.leafAt(index)
+	if index - leafStart < leafStart + leaf.length - index {
+		return leafStart
+	}
+	return leafStart + leaf.length
+}
+
+// Returns the rope followed by the others, linked in a balanced tree
+// sharing all their nodes, so only O(len(others)) nodes are created,
+// and nothing is copied except short leaves being joined.
+func (r *Rope[T]) Concat(others ...*Rope[T]) *Rope[T] {
+	ropes := []*Rope[T]{}
+	length := 0
+	for _, rope := range append([]*Rope[T]{r}, others...) {
+		if rope.length > math.MaxInt - length {
+			panic(fmt.Errorf("%w: adding %d to %d", ErrTooLong, rope.length, length))
+		}
+		if rope.length > 0 {
+			ropes = append(ropes, rope)
+			length += rope.length
+		}
+	}
+	switch len(ropes) {
+	case 0:
+		return r
+	case 1:
+		return ropes[0]
+	}
+	changed := concat(ropes).limitDepth(0)
+	changed = changed.autoRebalance()
+	changed.logOperation("Concat", r.length, length)
+	return changed
+}
+
+func concat[T any](ropes []*Rope[T]) *Rope[T] {
+	if len(ropes) == 1 {
+		return ropes[0]
+	}
+	return link(concat(ropes[:len(ropes) / 2]), concat(ropes[len(ropes) / 2:]))
+}
@@ -0,0 +1,75 @@
+package rope
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSubRope(t *testing.T) {
+	value := make([]int, 100)
+	for i := range value {
+		value[i] = i
+	}
+	rope := NewRope(value, testSettings)
+	for start := 0; start <= 100; start += 7 {
+		for end := start; end <= 100; end += 11 {
+			sub := rope.SubRope(start, end)
+			assertValue(t, sub, value[start:end])
+			assert(t, sub.Validate() == nil, "Invalid rope:", sub.Validate())
+		}
+	}
+	assert(t, rope.SubRope(0, 100) == rope, "Whole rope not reused")
+	sub := rope.SubRope(3, 97)
+	assert(t, ShareStats(rope, sub).Nodes > rope.Stats().Nodes / 2, "Nodes not shared")
+	for _, leaf := range leavesOf(sub) {
+		position := leaf[0]
+		assert(t, &leaf[0] == &value[position], "Leaf copied at", position)
+	}
+}
+
+func TestSplitFunc(t *testing.T) {
+	text := []byte("first record\nsecond\n\nthe last one, longer than a leaf")
+	rope := NewRope(text, testSettings)
+	parts := rope.SplitFunc(func(b byte) bool { return b == '\n' })
+	expected := bytes.Split(text, []byte("\n"))
+	assert(t, len(parts) == len(expected), "Wrong number of parts:", len(parts))
+	for i := range parts {
+		assert(t, bytes.Equal(parts[i].Value(), expected[i]), "Wrong part:", string(parts[i].Value()))
+	}
+	assert(t, len(Empty[byte](testSettings).SplitFunc(func(byte) bool { return true })) == 1, "Empty rope not one part")
+}
+
+func TestSplitN(t *testing.T) {
+	value := make([]int, 1000)
+	for i := range value {
+		value[i] = i
+	}
+	rope := NewRope(value, testSettings)
+	parts := rope.SplitN(4)
+	assert(t, len(parts) == 4, "Wrong number of parts:", len(parts))
+	offset := 0
+	for _, part := range parts {
+		assertValue(t, part, value[offset:offset + part.Length()])
+		assert(t, part.Length() >= 250 - testSettings.SplitLength && part.Length() <= 250 + testSettings.SplitLength,
+			"Part not about a quarter:", part.Length())
+		assert(t, ShareStats(rope, part).Nodes > part.Stats().Nodes / 2, "Nodes not shared")
+		for _, leaf := range leavesOf(part) {
+			assert(t, &leaf[0] == &value[leaf[0]] && len(leaf) >= testSettings.JoinLength, "Leaf cut at", leaf[0])
+		}
+		offset += part.Length()
+	}
+	assert(t, offset == 1000, "Parts don't cover the rope")
+	assertSameValue(t, parts[0].Concat(parts[1:]...), rope)
+
+	few := NewRope([]int{1, 2, 3}, testSettings).SplitN(3)
+	assert(t, len(few) == 3 && few[0].Length() + few[1].Length() + few[2].Length() == 3, "Wrong parts of a leaf")
+	assert(t, len(Empty[int](testSettings).SplitN(0)) == 1, "Wrong parts of an empty rope")
+}
+
+func TestConcat(t *testing.T) {
+	a, b := NewRope([]int{0, 1, 2, 3, 4, 5}, testSettings), NewRope([]int{6, 7}, testSettings)
+	joined := a.Concat(Empty[int](testSettings), b, a)
+	assertValue(t, joined, []int{0, 1, 2, 3, 4, 5, 6, 7, 0, 1, 2, 3, 4, 5})
+	assert(t, joined.Validate() == nil, "Invalid rope:", joined.Validate())
+	assert(t, a.Concat() == a && Empty[int](testSettings).Concat(b) == b, "Single rope not reused")
+}
@@ -0,0 +1,45 @@
+package rope
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// A value computed from the content of a node, kept in a list on the node.
+// As nodes never change their content, summaries never go stale, and
+// new versions only compute them for the nodes they don't share.
+type summary struct {
+	key   any
+	value any
+	next  *summary
+}
+
+// Returns the summary of the node for the key, computing it the first
+// time. It is safe to call concurrently, though compute may then be
+// called more than once.
+func cachedSummary[T, S any](r *Rope[T], key any, compute func() S) S {
+	head := (*summary)(atomic.LoadPointer(&r.summaries))
+	for {
+		for cached := head; cached != nil; cached = cached.next {
+			if cached.key == key {
+				return cached.value.(S)
+			}
+		}
+		value := compute()
+		added := &summary{key: key, value: value, next: head}
+		if atomic.CompareAndSwapPointer(&r.summaries, unsafe.Pointer(head), unsafe.Pointer(added)) {
+			return value
+		}
+		head = (*summary)(atomic.LoadPointer(&r.summaries)) // Someone else added one, check again
+	}
+}
+
+// Returns the memory of the elements, as bytes
+func elementBytes[T any](values []T) []byte {
+	var element T
+	size := int(unsafe.Sizeof(element))
+	if len(values) == 0 || size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&values[0])), len(values) * size)
+}
@@ -0,0 +1,80 @@
+package rope
+
+import "fmt"
+
+// Parts each range of a summary is split into
+const syncFanout = 16
+
+type Range struct {
+	Start, End int
+}
+
+// Hashes of the parts of some ranges of a rope, sent to another holder
+// of a copy to find which of them differ in it. It only holds lengths
+// and hashes, so it can be sent to another process.
+type SyncSummary struct {
+	Length int // Of the rope summarized
+	Parts  []RangeHash
+}
+
+type RangeHash struct {
+	Range
+	Hash [16]byte
+}
+
+// Summarizes the ranges, or the whole rope if there are none, with the
+// hashes of syncFanout parts of each one, or of the whole range if it is
+// not longer than SplitLength. Two holders of a copy reconcile them by
+// sending the summary of the ranges MissingRanges returned for the last
+// one, until they are short enough to send, so it takes O(log n) round
+// trips. Edits changing the length shift the ranges after them, so they
+// are all found to differ. Only byte ropes are summarized, as other
+// elements are hashed by their memory, which differs between processes
+// for the types holding pointers.
+func Summary(r *Rope[byte], ranges ...Range) SyncSummary {
+	if len(ranges) == 0 {
+		ranges = []Range{{0, r.length}}
+	}
+	summary := SyncSummary{Length: r.length}
+	for _, summarized := range ranges {
+		// Ranges past the end, as MissingRanges returns for a longer rope, are skipped
+		start, end := r.mustRange(min(summarized.Start, r.length), min(summarized.End, r.length))
+		if start == end && start < summarized.End {
+			continue
+		}
+		parts := 1
+		if end - start > r.settings.SplitLength {
+			parts = syncFanout
+		}
+		for i := 0; i < parts; i++ {
+			part := Range{start + (end - start) * i / parts, start + (end - start) * (i + 1) / parts}
+			if part.Start < part.End || parts == 1 {
+				summary.Parts = append(summary.Parts, RangeHash{part, r.rangeFingerprint(part.Start, part.End).sum()})
+			}
+		}
+	}
+	return summary
+}
+
+// Returns the parts of the summary which differ in the rope, and the
+// range past the end of the summarized rope if this one is longer. As the
+// summary comes from another holder, it fails with ErrInvalidRange if a
+// part or the length couldn't be of a rope.
+func MissingRanges(r *Rope[byte], summary SyncSummary) ([]Range, error) {
+	if summary.Length < 0 {
+		return nil, fmt.Errorf("%w: summary of length %d", ErrInvalidRange, summary.Length)
+	}
+	missing := []Range{}
+	for i, part := range summary.Parts {
+		if part.Start < 0 || part.Start > part.End {
+			return nil, fmt.Errorf("%w: part %d is [%d, %d)", ErrInvalidRange, i, part.Start, part.End)
+		}
+		if part.End > r.length || r.rangeFingerprint(part.Start, part.End).sum() != part.Hash {
+			missing = append(missing, part.Range)
+		}
+	}
+	if r.length > summary.Length {
+		missing = append(missing, Range{summary.Length, r.length})
+	}
+	return missing, nil
+}
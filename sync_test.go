@@ -0,0 +1,55 @@
+package rope
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	value := make([]byte, 10000)
+	for i := range value {
+		value[i] = byte(i % 251)
+	}
+	local := NewRope(value, DefaultSettings)
+	remote := NewRope(value, testSettings).Replace(1234, []byte{255}).Replace(8000, []byte{254})
+
+	ranges := []Range{}
+	rounds := 0
+	for {
+		rounds++
+		missing, err := MissingRanges(remote, Summary(local, ranges...))
+		assert(t, err == nil, "Unexpected error:", err)
+		short := true
+		for _, part := range missing {
+			short = short && part.End - part.Start <= local.settings.SplitLength
+		}
+		ranges = missing
+		if short {
+			break
+		}
+	}
+	assert(t, rounds <= 3, "Too many round trips:", rounds)
+	assert(t, len(ranges) == 2, "Wrong ranges:", ranges)
+	assert(t, ranges[0].Start <= 1234 && 1234 < ranges[0].End, "Wrong range:", ranges[0])
+	assert(t, ranges[1].Start <= 8000 && 8000 < ranges[1].End, "Wrong range:", ranges[1])
+
+	missing, _ := MissingRanges(local, Summary(NewRope(value, testSettings)))
+	assert(t, len(missing) == 0, "Equal copies differ:", missing)
+	longer := local.Append([]byte{1, 2})
+	missing, _ = MissingRanges(longer, Summary(local))
+	assert(t, len(missing) == 1 && missing[0] == Range{10000, 10002}, "Wrong range past the end:", missing)
+	assert(t, len(Summary(local, missing...).Parts) == 0, "Range past the end summarized")
+	missing, _ = MissingRanges(Empty[byte](testSettings), Summary(Empty[byte](testSettings)))
+	assert(t, len(missing) == 0, "Empty copies differ:", missing)
+}
+
+func TestMalformedSummary(t *testing.T) {
+	rope := NewRope([]byte("some text to summarize"), testSettings)
+	for _, part := range []Range{{50, 10}, {12, 10}, {-1, 5}} {
+		summary := SyncSummary{Length: rope.Length(), Parts: []RangeHash{{Range: part}}}
+		_, err := MissingRanges(rope, summary)
+		assert(t, errors.Is(err, ErrInvalidRange), "Malformed part accepted:", part, err)
+	}
+	_, err := MissingRanges(rope, SyncSummary{Length: -1})
+	assert(t, errors.Is(err, ErrInvalidRange), "Negative length accepted:", err)
+}
@@ -0,0 +1,15 @@
+package rope
+
+import "golang.org/x/text/transform"
+
+// Returns the bytes of the rope passed through the transformer, like an
+// encoding from golang.org/x/text/encoding, read leaf by leaf and written
+// directly into the leaves of the result, so the content is never copied
+// whole. The transformer is reset first.
+func Transform(r *Rope[byte], t transform.Transformer) (*Rope[byte], error) {
+	writer := NewWriter(r.settings)
+	if _, err := writer.ReadFrom(transform.NewReader(NewReader(r), t)); err != nil {
+		return nil, err
+	}
+	return writer.Rope(), nil
+}
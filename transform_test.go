@@ -0,0 +1,24 @@
+package rope
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestTransform(t *testing.T) {
+	latin1 := NewRope([]byte("caf\xe9 cr\xe8me br\xfbl\xe9e"), testSettings)
+	decoded, err := Transform(latin1, charmap.ISO8859_1.NewDecoder())
+	assert(t, err == nil, "Transform failed:", err)
+	assertValue(t, decoded, []byte("café crème brûlée"))
+	assert(t, decoded.Validate() == nil, "Invalid rope:", decoded.Validate())
+
+	encoded, err := Transform(decoded, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder())
+	assert(t, err == nil && encoded.Length() == 2 * 17, "Wrong UTF-16:", encoded.Length(), err)
+
+	_, err = Transform(NewRope([]byte("\xff"), testSettings), unicode.UTF8.NewDecoder())
+	assert(t, err == nil, "Replacement failed:", err)
+	_, err = Transform(NewRope([]byte("€"), testSettings), charmap.ISO8859_1.NewEncoder())
+	assert(t, err != nil, "Unencodable character accepted")
+}
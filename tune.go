@@ -0,0 +1,156 @@
+package rope
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+const tuneDuration = 50 * time.Millisecond // Minimum time to run each candidate
+
+// Runs the workload on an empty rope with each candidate settings,
+// and returns the fastest. If there are no candidates, SplitLengths from
+// 16 to 4096 are tried, with the rest like DefaultSettings.
+// The workload must be deterministic, for the comparison to be fair.
+func TuneSettings[T any](workload func(*Rope[T]), candidates []Settings) Settings {
+	if len(candidates) == 0 {
+		for splitLength := 16; splitLength <= 4096; splitLength *= 2 {
+			candidates = append(candidates, Settings{
+				SplitLength: splitLength,
+				JoinLength:  splitLength / 2,
+				Rebalance:   DefaultSettings.Rebalance,
+			})
+		}
+	}
+	best, bestTime := candidates[0], time.Duration(0)
+	for _, candidate := range candidates {
+		candidate := candidate
+		if perRun := timeWorkload(workload, &candidate); bestTime == 0 || perRun < bestTime {
+			best, bestTime = candidate, perRun
+		}
+	}
+	return best
+}
+
+func timeWorkload[T any](workload func(*Rope[T]), settings *Settings) time.Duration {
+	runs := 0
+	start := time.Now()
+	for runs < 3 || time.Since(start) < tuneDuration {
+		workload(NewRope([]T{}, settings))
+		runs++
+	}
+	perRun := time.Since(start) / time.Duration(runs)
+	if perRun == 0 {
+		perRun = 1 // So it can be told apart from no time measured
+	}
+	return perRun
+}
+
+// Records the lengths of the leaves split and joined, through the hooks
+// of the settings it is installed on, to suggest better lengths
+type Telemetry struct {
+	mutex       sync.Mutex
+	elementSize int
+	splits      [bits.UintSize]int // By bits.Len of the length split
+	joins       [bits.UintSize]int
+}
+
+// Creates a collector for ropes of T, which must be installed before use
+func NewTelemetry[T any]() *Telemetry {
+	var element T
+	return &Telemetry{elementSize: int(unsafe.Sizeof(element))}
+}
+
+// Sets OnSplit and OnJoin, calling the ones already set too
+func (t *Telemetry) Install(settings *Settings) {
+	onSplit, onJoin := settings.OnSplit, settings.OnJoin
+	settings.OnSplit = func(left, right int) {
+		t.record(&t.splits, left + right)
+		if onSplit != nil {
+			onSplit(left, right)
+		}
+	}
+	settings.OnJoin = func(length int) {
+		t.record(&t.joins, length)
+		if onJoin != nil {
+			onJoin(length)
+		}
+	}
+}
+
+func (t *Telemetry) record(histogram *[bits.UintSize]int, length int) {
+	t.mutex.Lock()
+	histogram[bits.Len(uint(length))]++
+	t.mutex.Unlock()
+}
+
+// Leaves of lengths from Min to Max, both included
+type Bucket struct {
+	Min    int
+	Max    int
+	Splits int
+	Joins  int
+}
+
+type TuningReport struct {
+	Splits      int
+	Joins       int
+	Histogram   []Bucket // Only the buckets with any leaves
+	SplitLength int      // Suggested
+	JoinLength  int      // Suggested
+}
+
+// Summarizes what was recorded, suggesting lengths from the histogram.
+// The SplitLength is the top of the bucket with the most splits and joins,
+// where the edits leave the leaves, capped for elements bigger than a byte
+// so leaves stay around the same size in bytes. The JoinLength is lowered
+// if leaves are joined almost as often as split, as then edits keep
+// splitting the leaves just joined. Without anything recorded, the lengths
+// in the settings are used instead.
+func (t *Telemetry) Report(settings *Settings) TuningReport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	report := TuningReport{SplitLength: settings.SplitLength, JoinLength: settings.JoinLength}
+	busiest := -1
+	for i := range t.splits {
+		if t.splits[i] == 0 && t.joins[i] == 0 {
+			continue
+		}
+		report.Histogram = append(report.Histogram, Bucket{
+			Min:    (1 << i) >> 1,
+			Max:    1 << i - 1,
+			Splits: t.splits[i],
+			Joins:  t.joins[i],
+		})
+		report.Splits += t.splits[i]
+		report.Joins += t.joins[i]
+		if busiest < 0 || t.splits[i] + t.joins[i] > t.splits[busiest] + t.joins[busiest] {
+			busiest = i
+		}
+	}
+	if busiest >= 0 {
+		report.SplitLength = max(2, 1 << busiest)
+		report.JoinLength = report.SplitLength / 2
+	}
+	if t.elementSize > 1 {
+		report.SplitLength = min(report.SplitLength, max(2, DefaultSettings.SplitLength / t.elementSize))
+		report.JoinLength = min(report.JoinLength, report.SplitLength / 2)
+	}
+	if report.Joins * 2 > report.Splits {
+		report.JoinLength = report.SplitLength / 4
+	}
+	return report
+}
+
+func (r TuningReport) String() string {
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "%d splits, %d joins\n", r.Splits, r.Joins)
+	for _, bucket := range r.Histogram {
+		fmt.Fprintf(builder, "%6d-%-6d splits %-6d joins %d\n", bucket.Min, bucket.Max, bucket.Splits, bucket.Joins)
+	}
+	fmt.Fprintf(builder, "suggested SplitLength %d, JoinLength %d\n", r.SplitLength, r.JoinLength)
+	return builder.String()
+}
@@ -0,0 +1,68 @@
+package rope
+
+import "testing"
+
+func TestTuneSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Tuning takes a while")
+	}
+	workload := func(rope *Rope[byte]) {
+		for j := 0; j < 2000; j++ {
+			rope = rope.Insert((j * 77777777) % (rope.Length() + 1), []byte{'a', 'b', 'c', 'd'})
+		}
+	}
+	tiny := Settings{SplitLength: 2, JoinLength: 1, Rebalance: 1.5}
+	candidates := []Settings{tiny, *DefaultSettings}
+
+	best := TuneSettings(workload, candidates)
+	assert(t, best.SplitLength == DefaultSettings.SplitLength, "Tiny leaves shouldn't win:", best)
+	best = TuneSettings(workload, nil)
+	assert(t, best.SplitLength >= 16 && best.SplitLength <= 4096, "Wrong default candidate:", best)
+}
+
+func TestTelemetry(t *testing.T) {
+	type big struct{ a, b, c, d int64 }
+	settings := *DefaultSettings
+	splits := 0
+	settings.OnSplit = func(left, right int) { splits++ }
+	telemetry := NewTelemetry[big]()
+	telemetry.Install(&settings)
+
+	rope := NewRope(make([]big, 1000), &settings)
+	for i := 0; i < 5; i++ {
+		rope = rope.Remove(10, 160)
+	}
+	report := telemetry.Report(&settings)
+	assert(t, report.Splits == splits && splits > 0, "Previous hook not called:", report.Splits, splits)
+	assert(t, report.Joins > 0, "Joins not recorded")
+	total := 0
+	for _, bucket := range report.Histogram {
+		assert(t, bucket.Min <= bucket.Max, "Wrong bucket:", bucket)
+		total += bucket.Splits + bucket.Joins
+	}
+	assert(t, total == report.Splits + report.Joins, "Histogram incomplete:", report.Histogram)
+	assert(t, report.SplitLength == 400 / 32, "Element size not used:", report.SplitLength)
+	assert(t, len(report.String()) > 0, "Empty report")
+}
+
+func TestTelemetrySkewed(t *testing.T) {
+	suggest := func(length int) TuningReport {
+		settings := *DefaultSettings
+		telemetry := NewTelemetry[byte]()
+		telemetry.Install(&settings)
+		for i := 0; i < 100; i++ {
+			settings.OnSplit(length / 2, length / 2)
+		}
+		settings.OnSplit(5000, 5000) // Outliers don't move the suggestion
+		settings.OnJoin(10)
+		return telemetry.Report(&settings)
+	}
+	small, large := suggest(40), suggest(3000)
+	assert(t, small.SplitLength == 64 && small.JoinLength == 32, "Wrong suggestion for short leaves:", small)
+	assert(t, large.SplitLength == 4096 && large.JoinLength == 2048, "Wrong suggestion for long leaves:", large)
+
+	settings := *DefaultSettings
+	report := NewTelemetry[byte]().Report(&settings)
+	assert(t, report.SplitLength == settings.SplitLength && report.JoinLength == settings.JoinLength,
+		"Settings not used without anything recorded:", report)
+}
@@ -0,0 +1,59 @@
+package rope
+
+import "fmt"
+
+// Checks the invariants of the structure: lengths of split nodes are
+// the sum of their sides, split nodes have both sides, neither of them
+// empty, and are not short enough to be joined, leaves are not long enough to be split,
+// and the depths kept in the nodes are right.
+func (r *Rope[T]) Validate() error {
+	return r.validate(0)
+}
+
+func (r *Rope[T]) validate(offset int) error {
+	if r.settings == nil {
+		return fmt.Errorf("%w: node at %d has no settings", ErrInvalidRope, offset)
+	}
+	if r.value != nil { // Isn't split
+		if r.left != nil || r.right != nil {
+			return fmt.Errorf("%w: leaf at %d has children", ErrInvalidRope, offset)
+		}
+		if r.height != 0 {
+			return fmt.Errorf("%w: leaf at %d has a depth over 1", ErrInvalidRope, offset)
+		}
+		if r.length != len(r.value) {
+			return fmt.Errorf("%w: leaf at %d has length %d, but %d elements",
+				ErrInvalidRope, offset, r.length, len(r.value))
+		}
+		if !r.piece && r.length > r.settings.SplitLength {
+			return fmt.Errorf("%w: leaf at %d is longer than SplitLength (%d > %d)",
+				ErrInvalidRope, offset, r.length, r.settings.SplitLength)
+		}
+		if r.piece && r.length <= r.settings.SplitLength {
+			return fmt.Errorf("%w: piece at %d is not longer than SplitLength", ErrInvalidRope, offset)
+		}
+		return nil
+	}
+	// Is split
+	if r.left == nil || r.right == nil {
+		return fmt.Errorf("%w: split node at %d is missing a side", ErrInvalidRope, offset)
+	}
+	if r.left.length == 0 || r.right.length == 0 {
+		return fmt.Errorf("%w: split node at %d has an empty side", ErrInvalidRope, offset)
+	}
+	if r.length != r.left.length + r.right.length {
+		return fmt.Errorf("%w: split node at %d has length %d, but its sides %d and %d",
+			ErrInvalidRope, offset, r.length, r.left.length, r.right.length)
+	}
+	if r.length < r.settings.JoinLength && r.settings.JoinLength <= r.settings.SplitLength {
+		return fmt.Errorf("%w: split node at %d is shorter than JoinLength (%d < %d)",
+			ErrInvalidRope, offset, r.length, r.settings.JoinLength)
+	}
+	if r.height != 1 + max(r.left.height, r.right.height) {
+		return fmt.Errorf("%w: split node at %d has the wrong depth", ErrInvalidRope, offset)
+	}
+	if err := r.left.validate(offset); err != nil {
+		return err
+	}
+	return r.right.validate(offset + r.left.length)
+}
@@ -0,0 +1,44 @@
+package rope
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	gapSettings := *testSettings
+	gapSettings.GapBuffer = true
+	lazySettings := *testSettings
+	lazySettings.LazyRemove = true
+
+	for _, settings := range []*Settings{testSettings, DefaultSettings, &gapSettings, &lazySettings} {
+		rope := NewPieceRope(make([]int, 1000), settings)
+		for i := 0; i < 300; i++ {
+			index := (i * 7919) % rope.Length()
+			switch i % 4 {
+			case 0:
+				end := index + (i % 20)
+				if end > rope.Length() {
+					end = rope.Length()
+				}
+				rope = rope.Remove(index, end)
+			case 1:
+				rope = rope.Append([]int{i, i})
+			default:
+				rope = rope.Insert(index, []int{i, i, i})
+			}
+			if err := rope.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	rope := NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	rope.left.length++
+	assert(t, errors.Is(rope.Validate(), ErrInvalidRope), "Wrong length not detected")
+
+	rope = NewRope([]int{0, 1, 2, 3, 4, 5, 6, 7}, testSettings)
+	rope.right = Empty[int](testSettings)
+	rope.length = rope.left.length
+	assert(t, errors.Is(rope.Validate(), ErrInvalidRope), "Empty side not detected")
+}
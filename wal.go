@@ -0,0 +1,112 @@
+package rope
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// A write-ahead log of the edits applied to a Document, so the ones not
+// yet saved can be replayed on the saved content after a crash. Each
+// record is written with a single Write:
+//
+//	uvarint size, start, end, encoded elements, crc32 of the rest
+type WAL[T any] struct {
+	w io.Writer
+}
+
+// Logs to w, syncing it after every record if it has a Sync method,
+// like *os.File
+func NewWAL[T any](w io.Writer) *WAL[T] {
+	return &WAL[T]{w}
+}
+
+func (l *WAL[T]) Append(edit Edit[T]) error {
+	encoded, err := encodeElements(edit.With)
+	if err != nil {
+		return err
+	}
+	payload := binary.AppendUvarint(nil, uint64(edit.Start))
+	payload = binary.AppendUvarint(payload, uint64(edit.End))
+	payload = append(payload, encoded...)
+	record := binary.AppendUvarint(nil, uint64(len(payload)))
+	record = append(record, payload...)
+	record = binary.LittleEndian.AppendUint32(record, crc32.ChecksumIEEE(payload))
+	if _, err := l.w.Write(record); err != nil {
+		return err
+	}
+	if syncer, ok := l.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// Applies the edits in the log to the document, in order, stopping at
+// the first record that is incomplete or fails its checksum, as the
+// last one can be if the process crashed while writing it. Returns how
+// many were applied.
+func ReplayWAL[T any](d *Document[T], wal io.Reader) (int, error) {
+	buffered := bufio.NewReader(wal)
+	replayed := 0
+	for {
+		edit, ok, err := readWALRecord[T](buffered)
+		if err != nil || !ok {
+			return replayed, err
+		}
+		if _, err := d.Apply(edit); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+}
+
+// Returns false if the log ends, or the record is torn or corrupted
+func readWALRecord[T any](r *bufio.Reader) (Edit[T], bool, error) {
+	size, err := readUvarint(r)
+	if err == io.ErrUnexpectedEOF || errors.Is(err, ErrInvalidEncoding) {
+		return Edit[T]{}, false, nil
+	}
+	if err != nil {
+		return Edit[T]{}, false, err
+	}
+	var record bytes.Buffer // Grown as it is read, so a corrupted size can't allocate much
+	if _, err := io.CopyN(&record, r, int64(size) + 4); err == io.EOF {
+		return Edit[T]{}, false, nil
+	} else if err != nil {
+		return Edit[T]{}, false, err
+	}
+	payload, checksum := record.Bytes()[:size], record.Bytes()[size:]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(checksum) {
+		return Edit[T]{}, false, nil
+	}
+	start, n := binary.Uvarint(payload)
+	if n <= 0 {
+		return Edit[T]{}, false, nil
+	}
+	end, m := binary.Uvarint(payload[n:])
+	if m <= 0 {
+		return Edit[T]{}, false, nil
+	}
+	with, err := decodeElements[T](payload[n + m:])
+	if err != nil {
+		return Edit[T]{}, false, nil
+	}
+	return Edit[T]{int(start), int(end), with}, true, nil
+}
+
+// Restores a byte document from the saved content and the log of the
+// edits applied on it since, as NewDocument plus ReplayWAL
+func Recover(base io.Reader, wal io.Reader, settings *Settings) (*Document[byte], error) {
+	content, err := io.ReadAll(base)
+	if err != nil {
+		return nil, err
+	}
+	document := NewDocument(NewRope(content, settings))
+	if _, err := ReplayWAL(document, wal); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
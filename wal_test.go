@@ -0,0 +1,61 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWAL(t *testing.T) {
+	var log bytes.Buffer
+	document := NewDocument(NewRope([]byte("hello world"), testSettings))
+	document.SetWAL(NewWAL[byte](&log))
+	document.Apply(Edit[byte]{0, 5, []byte("goodbye")})
+	document.Apply(Edit[byte]{7, 7, []byte(",")})
+	document.Apply(Edit[byte]{9, 14, nil})
+	document.Apply(Edit[byte]{100, 100, []byte("out of range")})
+	assertValue(t, document.Rope(), []byte("goodbye, "))
+
+	recovered, err := Recover(strings.NewReader("hello world"), bytes.NewReader(log.Bytes()), testSettings)
+	assert(t, err == nil, "Recover failed:", err)
+	assertSameValue(t, recovered.Rope(), document.Rope())
+	assert(t, recovered.Version() == 3, "Wrong version:", recovered.Version())
+
+	torn := log.Bytes()[:log.Len() - 3]
+	recovered, err = Recover(strings.NewReader("hello world"), bytes.NewReader(torn), testSettings)
+	assert(t, err == nil, "Torn record not skipped:", err)
+	assertValue(t, recovered.Rope(), []byte("goodbye, world"))
+
+	corrupted := bytes.Clone(log.Bytes())
+	corrupted[3]++
+	recovered, _ = Recover(strings.NewReader("hello world"), bytes.NewReader(corrupted), testSettings)
+	assertValue(t, recovered.Rope(), []byte("hello world"))
+
+	_, err = Recover(strings.NewReader("hello world"), iotest.ErrReader(io.ErrClosedPipe), testSettings)
+	assert(t, errors.Is(err, io.ErrClosedPipe), "Read error not returned:", err)
+}
+
+func TestWALFile(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "wal"))
+	assert(t, err == nil, "Create failed:", err)
+	defer file.Close()
+	document := NewDocument(NewRope([]int{0, 1, 2}, testSettings))
+	document.SetWAL(NewWAL[int](file))
+	_, err = document.Apply(Edit[int]{1, 2, []int{-1, -2}})
+	assert(t, err == nil, "Apply failed:", err)
+
+	file.Seek(0, io.SeekStart)
+	recovered := NewDocument(NewRope([]int{0, 1, 2}, testSettings))
+	replayed, err := ReplayWAL(recovered, file)
+	assert(t, err == nil && replayed == 1, "Replay failed:", replayed, err)
+	assertValue(t, recovered.Rope(), []int{0, -1, -2, 2})
+
+	file.Close()
+	_, err = document.Apply(Edit[int]{0, 0, []int{5}})
+	assert(t, err != nil && document.Version() == 1, "Edit applied without logging it")
+}
@@ -0,0 +1,206 @@
+package rope
+
+import (
+	"bufio"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+type widthKey struct{}
+
+// The display width of the UTF-8 text in a node, and its line breaks.
+// Characters can be split between nodes, so the bytes at the ends which
+// may belong to one are kept, to be measured when the nodes are combined.
+type widthSummary struct {
+	width    int    // Of the characters with all their bytes in the node
+	newlines int
+	lead     []byte // Up to 3 continuation bytes at the start, maybe ending a character
+	trail    []byte // The bytes of a character started but not ended at the end
+	whole    bool   // Whether all the bytes are in lead
+}
+
+// Returns the columns the character takes in a terminal: 2 for East Asian
+// wide characters, 0 for combining marks, format and control characters,
+// and 1 for the rest, including invalid UTF-8
+func runeWidth(char rune) int {
+	switch {
+	case char < 0x20 || (char >= 0x7f && char < 0xa0):
+		return 0
+	case unicode.In(char, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(char).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Returns the width of the bytes decoded on their own
+func bytesWidth(data []byte) int {
+	total := 0
+	for len(data) > 0 {
+		char, size := utf8.DecodeRune(data)
+		total += runeWidth(char)
+		data = data[size:]
+	}
+	return total
+}
+
+func leafWidthSummary(data []byte) widthSummary {
+	summary := widthSummary{}
+	start := 0
+	for start < len(data) && start < utf8.UTFMax - 1 && !utf8.RuneStart(data[start]) {
+		start++
+	}
+	summary.lead = data[:start:start]
+	summary.whole = start == len(data)
+	for i := start; i < len(data); {
+		if !utf8.FullRune(data[i:]) {
+			summary.trail = data[i:len(data):len(data)]
+			break
+		}
+		char, size := utf8.DecodeRune(data[i:])
+		summary.width += runeWidth(char)
+		if char == '\n' {
+			summary.newlines++
+		}
+		i += size
+	}
+	return summary
+}
+
+// Returns the summary of left followed by right
+func combineWidthSummaries(left, right widthSummary) widthSummary {
+	if left.whole {
+		lead := append(append([]byte{}, left.lead...), right.lead...)
+		extra := max(0, len(lead) - (utf8.UTFMax - 1)) // Can't belong to a character, so are invalid
+		combined := right
+		combined.lead = lead[:len(lead) - extra]
+		combined.width += extra
+		combined.whole = right.whole && extra == 0
+		return combined
+	}
+	combined := widthSummary{
+		width:    left.width + right.width,
+		newlines: left.newlines + right.newlines,
+		lead:     left.lead,
+		trail:    right.trail,
+	}
+	junction := append(append([]byte{}, left.trail...), right.lead...)
+	if right.whole {
+		if len(left.trail) > 0 && !utf8.FullRune(junction) {
+			combined.trail = junction // The character may still end later
+			return combined
+		}
+		combined.trail = nil
+	}
+	combined.width += bytesWidth(junction)
+	return combined
+}
+
+// Returns the width of the whole text, with the bytes at the ends, which
+// are not part of a character, as invalid
+func (s widthSummary) total() int {
+	return s.width + bytesWidth(s.lead) + bytesWidth(s.trail)
+}
+
+func nodeWidthSummary(r *Rope[byte]) widthSummary {
+	return cachedSummary(r, widthKey{}, func() widthSummary {
+		if r.value != nil {
+			return leafWidthSummary(r.value)
+		}
+		return combineWidthSummaries(nodeWidthSummary(r.left), nodeWidthSummary(r.right))
+	})
+}
+
+// Returns the summary of the range, from the ones cached on the nodes
+// inside it, measuring only the parts of the leaves at its ends
+func rangeWidthSummary(r *Rope[byte], start, end int) widthSummary {
+	if start == 0 && end == r.length {
+		return nodeWidthSummary(r)
+	}
+	if r.value != nil {
+		return leafWidthSummary(r.value[start:end])
+	}
+	leftStart, leftEnd := bound(start, end, r.left.length)
+	rightStart, rightEnd := bound(start - r.left.length, end - r.left.length, r.right.length)
+	if rightStart == rightEnd {
+		return rangeWidthSummary(r.left, leftStart, leftEnd)
+	}
+	if leftStart == leftEnd {
+		return rangeWidthSummary(r.right, rightStart, rightEnd)
+	}
+	return combineWidthSummaries(rangeWidthSummary(r.left, leftStart, leftEnd), rangeWidthSummary(r.right, rightStart, rightEnd))
+}
+
+// Returns the columns the UTF-8 text in the range takes in a terminal,
+// in O(log n) from widths cached on the nodes. Line breaks take none.
+func WidthRange(r *Rope[byte], start, end int) int {
+	start, end = r.mustRange(start, end)
+	return rangeWidthSummary(r, start, end).total()
+}
+
+// Returns the offset of the line, from the line breaks counted on the nodes.
+// Panics with ErrIndexOutOfRange if there are not that many lines.
+func LineStart(r *Rope[byte], line int) int {
+	if newlines := nodeWidthSummary(r).newlines; line < 0 || line > newlines {
+		panic(fmt.Errorf("%w: line %d with %d line breaks", ErrIndexOutOfRange, line, newlines))
+	}
+	offset := 0
+	for line > 0 && r.value == nil {
+		if left := nodeWidthSummary(r.left).newlines; line <= left {
+			r = r.left
+		} else {
+			line -= left
+			offset += r.left.length
+			r = r.right
+		}
+	}
+	for i := 0; ; i++ {
+		if line == 0 {
+			return offset + i
+		}
+		if r.value[i] == '\n' {
+			line--
+		}
+	}
+}
+
+// Returns the offset of the line break ending the line, or the length
+// for the last line
+func lineEnd(r *Rope[byte], line int) int {
+	if line == nodeWidthSummary(r).newlines {
+		return r.length
+	}
+	return LineStart(r, line + 1) - 1
+}
+
+// Returns the line the offset is in, counting the line breaks before it
+func LineAt(r *Rope[byte], offset int) int {
+	offset = r.mustIndex(offset, r.length)
+	return rangeWidthSummary(r, 0, offset).newlines
+}
+
+// Returns the offset of the character of the line at the column, or of
+// the end of the line if it is shorter. A wide character starting before
+// the column and covering it is returned. Only the line is read, up to
+// the offset.
+func OffsetAtWidth(r *Rope[byte], line, column int) int {
+	offset := LineStart(r, line)
+	reader := bufio.NewReader(NewSectionReader(r, offset, r.length))
+	for {
+		char, size, err := reader.ReadRune()
+		if err != nil || char == '\n' {
+			return offset
+		}
+		column -= runeWidth(char)
+		if column < 0 {
+			return offset
+		}
+		offset += size
+	}
+}
@@ -0,0 +1,33 @@
+package rope
+
+import "testing"
+
+func TestWidthRange(t *testing.T) {
+	// With leaves of 4, most characters are split between leaves
+	text := []byte("a世界\né😀\xe4\xb8x\x80\x80\x80\x80ｂ\n")
+	rope := NewRope(text, testSettings)
+	assert(t, WidthRange(rope, 0, len(text)) == 17, "Wrong width", WidthRange(rope, 0, len(text)))
+	for start := 0; start <= len(text); start++ {
+		for end := start; end <= len(text); end++ {
+			assert(t, WidthRange(rope, start, end) == bytesWidth(text[start:end]),
+				"Wrong width for", start, end, ":", WidthRange(rope, start, end))
+		}
+	}
+	edited := rope.Insert(7, []byte("中"))
+	assert(t, WidthRange(edited, 0, edited.length) == 19, "Wrong width after insert")
+	assertPanics(t, ErrIndexOutOfRange, func() { WidthRange(rope, 0, len(text) + 1) })
+}
+
+func TestOffsetAtWidth(t *testing.T) {
+	text := []byte("ab\n世界x\n\ne\u0301f")
+	rope := NewRope(text, testSettings)
+	assert(t, LineStart(rope, 0) == 0 && LineStart(rope, 1) == 3 && LineStart(rope, 3) == 12, "Wrong line starts")
+	assert(t, OffsetAtWidth(rope, 0, 1) == 1, "Wrong offset in ASCII")
+	assert(t, OffsetAtWidth(rope, 0, 5) == 2, "Wrong offset past the line")
+	assert(t, OffsetAtWidth(rope, 1, 2) == 6, "Wrong offset after a wide character")
+	assert(t, OffsetAtWidth(rope, 1, 3) == 6, "Wrong offset inside a wide character")
+	assert(t, OffsetAtWidth(rope, 1, 4) == 9, "Wrong offset after wide characters")
+	assert(t, OffsetAtWidth(rope, 2, 0) == 11, "Wrong offset in an empty line")
+	assert(t, OffsetAtWidth(rope, 3, 1) == 12 + 3, "Wrong offset after a combining mark")
+	assertPanics(t, ErrIndexOutOfRange, func() { LineStart(rope, 4) })
+}
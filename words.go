@@ -0,0 +1,163 @@
+package rope
+
+import (
+	"bufio"
+	"iter"
+	"unicode"
+)
+
+// Classes of characters for Words, a simplification of the word break
+// properties of UAX #29
+const (
+	wordNone = iota
+	wordLetter   // Letters, digits, marks and connectors like _
+	wordSpace    // Spaces other than line breaks
+	wordNewline  // \n, \r, or \r\n
+	wordOther    // Anything else, each one a segment of its own
+)
+
+func wordClass(char rune) int {
+	switch {
+	case unicode.IsLetter(char) || unicode.IsDigit(char) || unicode.IsMark(char) || unicode.Is(unicode.Pc, char):
+		return wordLetter
+	case char == '\n' || char == '\r':
+		return wordNewline
+	case unicode.IsSpace(char):
+		return wordSpace
+	}
+	return wordOther
+}
+
+// Characters joining letters into a word when between them, like in
+// "can't" or "3.14"
+func isMidWord(char rune) bool {
+	return char == '\'' || char == '’' || char == '.' || char == ':' || char == ','
+}
+
+// Yields the segments between word boundaries of the UTF-8 text in the
+// range: words, runs of spaces, line breaks, and each other character,
+// so they cover the range. Words keep apostrophes, periods, colons and
+// commas between their letters. As Chunks, the range is checked when
+// iterating.
+func Words(r *Rope[byte], start, end int) iter.Seq[Range] {
+	return func(yield func(Range) bool) {
+		start, end := r.mustRange(start, end)
+		reader := bufio.NewReader(NewSectionReader(r, start, end))
+		segmentStart, class := start, wordNone
+		mid := -1 // Where a character after a word, which may join it, is
+		var previous rune
+		// Yields the segment until the offset, if it isn't empty
+		emit := func(offset int) bool {
+			if offset == segmentStart {
+				return true
+			}
+			segment := Range{segmentStart, offset}
+			segmentStart = offset
+			return yield(segment)
+		}
+		for offset := start; ; {
+			char, size, err := reader.ReadRune()
+			if err != nil {
+				break
+			}
+			current := wordClass(char)
+			if mid >= 0 {
+				if current == wordLetter { // The character joins the word
+					mid = -1
+					offset += size
+					continue
+				}
+				if !emit(mid) || !emit(offset) {
+					return
+				}
+				mid, class = -1, wordNone
+			}
+			continues := current == class && current != wordOther && current != wordNewline
+			switch {
+			case class == wordLetter && isMidWord(char):
+				mid = offset
+			case class == wordNewline && previous == '\r' && char == '\n':
+			case continues:
+			default:
+				if !emit(offset) {
+					return
+				}
+				class = current
+			}
+			previous = char
+			offset += size
+		}
+		if mid >= 0 && !emit(mid) {
+			return
+		}
+		emit(end)
+	}
+}
+
+// Yields the sentences of the UTF-8 text in the range, covering it.
+// A sentence ends after a line break, or after ., ! or ? followed by
+// closing quotes or brackets and spaces, unless it is a period followed
+// by a lowercase letter, as in "e.g. this". As Chunks, the range is
+// checked when iterating.
+func Sentences(r *Rope[byte], start, end int) iter.Seq[Range] {
+	return func(yield func(Range) bool) {
+		start, end := r.mustRange(start, end)
+		reader := bufio.NewReader(NewSectionReader(r, start, end))
+		sentenceStart := start
+		const (
+			inSentence = iota
+			afterTerminator // Then, closing characters can follow
+			afterSpace      // Then, the next sentence can start
+		)
+		state := inSentence
+		var terminator rune
+		for offset := start; ; {
+			char, size, err := reader.ReadRune()
+			if err != nil {
+				break
+			}
+			if state == afterSpace && !unicode.IsSpace(char) {
+				if terminator != '.' || !unicode.IsLower(char) {
+					if !yield(Range{sentenceStart, offset}) {
+						return
+					}
+					sentenceStart = offset
+				}
+				state = inSentence
+			}
+			if state == afterTerminator && !isTerminator(char) && !isCloser(char) {
+				state = inSentence
+				if unicode.IsSpace(char) {
+					state = afterSpace
+				}
+			}
+			offset += size
+			switch {
+			case char == '\n' || (char == '\r' && !nextIs(reader, '\n')):
+				if !yield(Range{sentenceStart, offset}) {
+					return
+				}
+				sentenceStart, state = offset, inSentence
+			case state == inSentence && isTerminator(char):
+				state, terminator = afterTerminator, char
+			}
+		}
+		if end > sentenceStart {
+			yield(Range{sentenceStart, end})
+		}
+	}
+}
+
+func isTerminator(char rune) bool {
+	return char == '.' || char == '!' || char == '?'
+}
+
+func isCloser(char rune) bool {
+	return char == '"' || char == '\'' || char == '”' || char == '’' || char == ')' || char == ']'
+}
+
+// Reports whether the next character is char, without reading it
+func nextIs(reader *bufio.Reader, char byte) bool {
+	next, err := reader.Peek(1)
+	return err == nil && next[0] == char
+}
@@ -0,0 +1,39 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+func segments(r *Rope[byte], ranges []Range) []string {
+	texts := []string{}
+	for _, segment := range ranges {
+		texts = append(texts, string(r.Slice(segment.Start, segment.End)))
+	}
+	return texts
+}
+
+func TestWords(t *testing.T) {
+	rope := NewRope([]byte("Héllo, wörld!  can't 3.14 foo_bar.\r\n\nend"), testSettings)
+	words := segments(rope, slices.Collect(Words(rope, 0, rope.Length())))
+	expected := []string{"Héllo", ",", " ", "wörld", "!", "  ", "can't", " ", "3.14", " ", "foo_bar", ".", "\r\n", "\n", "end"}
+	assert(t, slices.Equal(words, expected), "Wrong words:", words)
+
+	words = segments(rope, slices.Collect(Words(rope, 1, 11)))
+	assert(t, slices.Equal(words, []string{"éllo", ",", " ", "wö"}), "Wrong words in range:", words)
+	for range Words(rope, 0, rope.Length()) {
+		break
+	}
+	assertPanics(t, ErrIndexOutOfRange, func() {
+		for range Words(rope, 0, 100) {
+		}
+	})
+}
+
+func TestSentences(t *testing.T) {
+	rope := NewRope([]byte("Hello there. How are you?! Fine, e.g. well. \"Quoted.\" Pi is 3.14\nNext line"), testSettings)
+	sentences := segments(rope, slices.Collect(Sentences(rope, 0, rope.Length())))
+	expected := []string{"Hello there. ", "How are you?! ", "Fine, e.g. well. ", "\"Quoted.\" ", "Pi is 3.14\n", "Next line"}
+	assert(t, slices.Equal(sentences, expected), "Wrong sentences:", sentences)
+	assert(t, len(slices.Collect(Sentences(rope, 0, 0))) == 0, "Sentences in an empty range")
+}
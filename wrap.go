@@ -0,0 +1,156 @@
+package rope
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+type WrapOptions struct {
+	Words bool // Break rows after the last space that fits, rather than anywhere
+}
+
+// Lays the lines of a byte rope out in rows of at most width columns,
+// as measured by WidthRange, only for the lines asked about, which are
+// kept until an edit touches them, so viewports of huge documents are
+// cheap to render. Rows have at least one character, even if it is wider.
+// Like cursors, wraps aren't safe for concurrent use.
+type Wrap struct {
+	rope    *Rope[byte]
+	width   int
+	options WrapOptions
+	breaks  map[int][]int // Where the rows after the first start, from the start of the line
+}
+
+func NewWrap(r *Rope[byte], width int, options WrapOptions) *Wrap {
+	return &Wrap{rope: r, width: max(width, 1), options: options, breaks: map[int][]int{}}
+}
+
+func (w *Wrap) Rope() *Rope[byte] {
+	return w.rope
+}
+
+// Changes the width, laying the lines out again
+func (w *Wrap) SetWidth(width int) {
+	w.width = max(width, 1)
+	clear(w.breaks)
+}
+
+// Changes the rope to r, the result of applying the edits, sorted by
+// Start as in a Patch, to the old one. Only the lines they touch are
+// laid out again, and the others keep their rows.
+func (w *Wrap) Update(r *Rope[byte], edits ...Edit[byte]) {
+	type shift struct{ first, last, delta int }
+	shifts := make([]shift, len(edits))
+	for i, edit := range edits {
+		first, last := LineAt(w.rope, edit.Start), LineAt(w.rope, edit.End)
+		shifts[i] = shift{first, last, bytes.Count(edit.With, []byte("\n")) - (last - first)}
+	}
+	kept := map[int][]int{}
+	for line, breaks := range w.breaks {
+		moved := line
+		for _, s := range shifts {
+			if line >= s.first && line <= s.last {
+				moved = -1
+				break
+			}
+			if line > s.last {
+				moved += s.delta
+			}
+		}
+		if moved >= 0 {
+			kept[moved] = breaks
+		}
+	}
+	w.rope, w.breaks = r, kept
+}
+
+func (w *Wrap) layout(line int) (start int, breaks []int) {
+	start = LineStart(w.rope, line)
+	if breaks, ok := w.breaks[line]; ok {
+		return start, breaks
+	}
+	end := lineEnd(w.rope, line)
+	breaks = []int{}
+	if WidthRange(w.rope, start, end) > w.width { // Most lines fit, and aren't read
+		breaks = wrapLine(w.rope, start, end, w.width, w.options)
+	}
+	w.breaks[line] = breaks
+	return start, breaks
+}
+
+// Returns where the rows after the first start, from start
+func wrapLine(r *Rope[byte], start, end, width int, options WrapOptions) []int {
+	breaks := []int{}
+	reader := bufio.NewReader(NewSectionReader(r, start, end))
+	rowStart, used := 0, 0
+	space, spaceUsed := -1, 0 // After the last space in the row, and the width until it
+	for offset := 0; ; {
+		char, size, err := reader.ReadRune()
+		if err != nil {
+			return breaks
+		}
+		charWidth := runeWidth(char)
+		for used + charWidth > width && offset > rowStart {
+			if options.Words && space > rowStart {
+				rowStart, used = space, used - spaceUsed
+			} else {
+				rowStart, used = offset, 0
+			}
+			breaks = append(breaks, rowStart)
+		}
+		used += charWidth
+		offset += size
+		if options.Words && unicode.IsSpace(char) {
+			space, spaceUsed = offset, used
+		}
+	}
+}
+
+// Returns the offsets where the rows of the line start
+func (w *Wrap) Rows(line int) []int {
+	start, breaks := w.layout(line)
+	rows := []int{start}
+	for _, offset := range breaks {
+		rows = append(rows, start + offset)
+	}
+	return rows
+}
+
+// Returns the line and the row in it the offset is in
+func (w *Wrap) Position(offset int) (line, row int) {
+	offset = w.rope.mustIndex(offset, w.rope.length)
+	line = LineAt(w.rope, offset)
+	start, breaks := w.layout(line)
+	return line, sort.SearchInts(breaks, offset - start + 1)
+}
+
+// Returns the offset where the row of the line starts.
+// Panics with ErrIndexOutOfRange if the line doesn't have it.
+func (w *Wrap) Offset(line, row int) int {
+	rows := w.Rows(line)
+	if row < 0 || row >= len(rows) {
+		panic(fmt.Errorf("%w: row %d with %d rows", ErrIndexOutOfRange, row, len(rows)))
+	}
+	return rows[row]
+}
+
+// Returns the ranges of up to count rows, from the row of the line,
+// without the line breaks, as shown in a viewport starting there
+func (w *Wrap) Viewport(line, row, count int) []Range {
+	ranges := []Range{}
+	lines := nodeWidthSummary(w.rope).newlines + 1
+	for ; line < lines && len(ranges) < count; line, row = line + 1, 0 {
+		rows := w.Rows(line)
+		if row < 0 || row >= len(rows) {
+			panic(fmt.Errorf("%w: row %d with %d rows", ErrIndexOutOfRange, row, len(rows)))
+		}
+		rows = append(rows, lineEnd(w.rope, line))
+		for ; row < len(rows) - 1 && len(ranges) < count; row++ {
+			ranges = append(ranges, Range{rows[row], rows[row + 1]})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,47 @@
+package rope
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	rope := NewRope([]byte("hello world foo\n世界世界\n\nab"), testSettings)
+	wrap := NewWrap(rope, 8, WrapOptions{})
+	assert(t, slices.Equal(wrap.Rows(0), []int{0, 8}), "Wrong rows", wrap.Rows(0))
+	wrap = NewWrap(rope, 8, WrapOptions{Words: true})
+	assert(t, slices.Equal(wrap.Rows(0), []int{0, 6, 12}), "Wrong rows at words", wrap.Rows(0))
+	assert(t, slices.Equal(wrap.Rows(1), []int{16}), "Wrong rows of a fitting line", wrap.Rows(1))
+	assert(t, slices.Equal(wrap.Rows(2), []int{29}), "Wrong rows of an empty line", wrap.Rows(2))
+
+	wrap.SetWidth(6)
+	assert(t, slices.Equal(wrap.Rows(1), []int{16, 25}), "Wrong rows of wide characters", wrap.Rows(1))
+	positions := [][3]int{{0, 0, 0}, {7, 0, 1}, {24, 1, 0}, {25, 1, 1}, {31, 3, 0}}
+	for _, p := range positions {
+		line, row := wrap.Position(p[0])
+		assert(t, line == p[1] && row == p[2], "Wrong position of", p[0], ":", line, row)
+	}
+	assert(t, wrap.Offset(1, 1) == 25, "Wrong offset of a row")
+	assertPanics(t, ErrIndexOutOfRange, func() { wrap.Offset(1, 2) })
+
+	wrap.SetWidth(8)
+	viewport := wrap.Viewport(0, 1, 4)
+	assert(t, slices.Equal(viewport, []Range{{6, 12}, {12, 15}, {16, 28}, {29, 29}}), "Wrong viewport", viewport)
+	assert(t, len(wrap.Viewport(3, 0, 10)) == 1, "Wrong viewport at the end")
+}
+
+func TestWrapUpdate(t *testing.T) {
+	rope := NewRope([]byte("hello world foo\n世界世界\n\nab"), testSettings)
+	wrap := NewWrap(rope, 8, WrapOptions{Words: true})
+	wrap.Viewport(0, 0, 10)
+	edit := Edit[byte]{30, 30, []byte("x\ny\n")}
+	wrap.Update(Patch[byte]{edit}.Apply(rope), edit)
+	assert(t, len(wrap.breaks) == 3, "Untouched lines not kept", wrap.breaks)
+	assert(t, slices.Equal(wrap.Rows(5), []int{34}), "Wrong rows after an insert", wrap.Rows(5))
+
+	edit = Edit[byte]{0, 5, []byte("hi")}
+	wrap.Update(Patch[byte]{edit}.Apply(wrap.Rope()), edit)
+	_, kept := wrap.breaks[1]
+	assert(t, kept && len(wrap.breaks) == 3, "Untouched lines not kept", wrap.breaks)
+	assert(t, slices.Equal(wrap.Rows(0), []int{0, 3, 9}), "Edited line not laid out again", wrap.Rows(0))
+}